@@ -0,0 +1,112 @@
+package lumberjack
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+//alertGuard rate limits the LogEntry objects at or above a minimum LogLevel
+//sent to alerting backends, so that a tight loop logging CRITICAL does not
+//storm a pager. It is a token bucket that starts full, so the first LogEntry
+//of a burst always goes through promptly.
+type alertGuard struct {
+	minLevel LogLevel
+	rate     float64
+	burst    float64
+	backends map[string]struct{}
+	tokens   float64
+	last     time.Time
+	clock    Clock
+	sync.Mutex
+}
+
+//SetAlertGuard rate limits the LogEntry objects at or above the specified
+//minimum LogLevel sent to the Backends added with the specified names, such
+//as those paging an on-call engineer. Up to burst LogEntry objects are let
+//through at once, refilling at rate LogEntry objects per second, and any
+//excess is dropped for those Backends only. Every other Backend still
+//receives every LogEntry.
+func (l *Logger) SetAlertGuard(minLevel LogLevel, rate float64, burst int, backends ...string) error {
+	if !validLevel(minLevel) {
+		return fmt.Errorf("Invalid LogLevel: %d", minLevel)
+	}
+	if rate <= 0 || burst <= 0 {
+		return fmt.Errorf("Alert guard rate and burst must be positive")
+	}
+
+	guard := &alertGuard{
+		minLevel: minLevel,
+		rate:     rate,
+		burst:    float64(burst),
+		backends: map[string]struct{}{},
+		tokens:   float64(burst),
+		clock:    systemClock{},
+	}
+	for _, name := range backends {
+		guard.backends[name] = struct{}{}
+	}
+
+	l.Lock()
+	defer l.Unlock()
+	l.alertGuard = guard
+	return nil
+}
+
+//RemoveAlertGuard stops rate limiting the LogEntry objects sent to alerting
+//backends.
+func (l *Logger) RemoveAlertGuard() {
+	l.Lock()
+	defer l.Unlock()
+	l.alertGuard = nil
+}
+
+//guards returns true if the specified LogEntry is rate limited for the
+//Backends guarded by the alertGuard.
+func (g *alertGuard) guards(entry *LogEntry) bool {
+	return entry.Level >= g.minLevel
+}
+
+//reset returns a copy of the alertGuard with the same settings and a full
+//bucket.
+func (g *alertGuard) reset() *alertGuard {
+	guard := &alertGuard{
+		minLevel: g.minLevel,
+		rate:     g.rate,
+		burst:    g.burst,
+		backends: map[string]struct{}{},
+		tokens:   g.burst,
+		clock:    g.clock,
+	}
+	for name := range g.backends {
+		guard.backends[name] = struct{}{}
+	}
+	return guard
+}
+
+//guarded returns true if the Backend with the specified name is guarded.
+func (g *alertGuard) guarded(name string) bool {
+	_, exists := g.backends[name]
+	return exists
+}
+
+//allow takes a token from the bucket and returns true if one was available.
+func (g *alertGuard) allow() bool {
+	g.Lock()
+	defer g.Unlock()
+
+	now := g.clock.Now()
+	if !g.last.IsZero() {
+		g.tokens += now.Sub(g.last).Seconds() * g.rate
+		if g.tokens > g.burst {
+			g.tokens = g.burst
+		}
+	}
+	g.last = now
+
+	if g.tokens < 1 {
+		return false
+	}
+	g.tokens--
+	return true
+}
@@ -0,0 +1,96 @@
+package lumberjack
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAlertGuard(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+	logger.AddLevel(CRITICAL)
+
+	pager := &captureBackend{}
+	archive := &captureBackend{}
+	logger.AddBackend("pager", pager)
+	logger.AddBackend("archive", archive)
+
+	if err := logger.SetAlertGuard(CRITICAL, 1, 2, "pager"); err != nil {
+		t.Fatal(err)
+	}
+
+	clock := newFakeClock()
+	logger.alertGuard.clock = clock
+
+	for i := 0; i < 10; i++ {
+		logger.Critical("storm")
+	}
+	logger.Info("not an alert")
+
+	//Only the burst reaches the pager, while the archive sees everything.
+	expect(t, len(pager.Entries()), 3)
+	expect(t, len(archive.Entries()), 11)
+
+	//The bucket refills at the rate over time.
+	clock.Advance(time.Second)
+	logger.Critical("after one second")
+	logger.Critical("still limited")
+	expect(t, len(pager.Entries()), 4)
+	expect(t, pager.Entries()[3].Message, "after one second")
+
+	logger.RemoveAlertGuard()
+	logger.Critical("unguarded")
+	expect(t, len(pager.Entries()), 5)
+}
+
+func TestAlertGuardInvalid(t *testing.T) {
+	logger := NewLogger()
+
+	if err := logger.SetAlertGuard(CRITICAL, 0, 1, "pager"); err == nil {
+		t.Error("Expected error for zero rate")
+	}
+	if err := logger.SetAlertGuard(CRITICAL, 1, 0, "pager"); err == nil {
+		t.Error("Expected error for zero burst")
+	}
+	if err := logger.SetAlertGuard(LogLevel(42), 1, 1, "pager"); err == nil {
+		t.Error("Expected error for invalid LogLevel")
+	}
+}
+
+func TestAlertGuardOnlyCountsGuardedReceivers(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(CRITICAL)
+
+	pager := &captureBackend{}
+	archive := &captureBackend{}
+	logger.AddBackend("pager", pager)
+	logger.AddBackend("archive", archive)
+
+	if err := logger.SetAlertGuard(CRITICAL, 1, 2, "pager"); err != nil {
+		t.Fatal(err)
+	}
+	logger.alertGuard.clock = newFakeClock()
+
+	//Entries routed away from the pager don't use up its tokens.
+	logger.Route(func(entry *LogEntry) bool { return entry.Message == "archived" }, "archive", true)
+	for i := 0; i < 10; i++ {
+		logger.Critical("archived")
+	}
+
+	//Nor do entries sent while the pager is disabled.
+	logger.DisableBackend("pager")
+	for i := 0; i < 10; i++ {
+		logger.Critical("while muted")
+	}
+	logger.EnableBackend("pager")
+
+	logger.Critical("first alert")
+	logger.Critical("second alert")
+	logger.Critical("limited")
+
+	entries := pager.Entries()
+	expect(t, len(entries), 2)
+	expect(t, entries[0].Message, "first alert")
+	expect(t, entries[1].Message, "second alert")
+	expect(t, len(archive.Entries()), 10) //Routed, so it only receives its matches.
+}
@@ -0,0 +1,38 @@
+package lumberjack
+
+import "fmt"
+
+//MaxAttachmentBytes is the maximum total size in bytes of the attachments a
+//Logger created with WithAttachment can hold.
+var MaxAttachmentBytes = 1 << 20
+
+//WithAttachment returns a child Logger that shares the configuration of the
+//current Logger and attaches the specified data under the specified name to
+//every CRITICAL and FATAL LogEntry it sends, along with any attachments of
+//the current Logger, such as a goroutine dump or configuration snapshot for a
+//crash report. Backends that support attachments, such as the JSON backends,
+//include them in the LogEntry. An error is returned if the total size of the
+//attachments would exceed MaxAttachmentBytes.
+func (l *Logger) WithAttachment(name string, data []byte) (*Logger, error) {
+	total := len(data)
+	for existing, attached := range l.attachments {
+		if existing != name {
+			total += len(attached)
+		}
+	}
+
+	if total > MaxAttachmentBytes {
+		return nil, fmt.Errorf("Attachments exceed the maximum size of %d bytes: %d", MaxAttachmentBytes, total)
+	}
+
+	attachments := map[string][]byte{name: data}
+	for existing, attached := range l.attachments {
+		if existing != name {
+			attachments[existing] = attached
+		}
+	}
+
+	child := *l
+	child.attachments = attachments
+	return &child, nil
+}
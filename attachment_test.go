@@ -0,0 +1,71 @@
+package lumberjack
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestWithAttachment(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+	logger.AddLevel(CRITICAL)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	child, err := logger.WithAttachment("goroutines", []byte("goroutine 1 [running]"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	child, err = child.WithAttachment("config", []byte("debug=true"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	child.Info("not an alert")
+	child.WithComponent("db").Critical("crashed")
+	logger.Critical("parent")
+
+	entries := capture.Entries()
+	expect(t, len(entries), 3)
+	expect(t, len(entries[0].Attachments), 0)
+	expect(t, entries[1].Attachments, map[string][]byte{
+		"goroutines": []byte("goroutine 1 [running]"),
+		"config":     []byte("debug=true"),
+	})
+	expect(t, len(entries[2].Attachments), 0)
+}
+
+func TestWithAttachmentTooLarge(t *testing.T) {
+	logger := NewLogger()
+
+	child, err := logger.WithAttachment("dump", make([]byte, MaxAttachmentBytes-10))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := child.WithAttachment("config", make([]byte, 11)); err == nil {
+		t.Error("Expected error for attachments exceeding the maximum size")
+	}
+
+	//Replacing an attachment only counts the new data.
+	if _, err := child.WithAttachment("dump", make([]byte, MaxAttachmentBytes)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestJSONBackendAttachments(t *testing.T) {
+	var buf bytes.Buffer
+	backend := NewJSONBackend(&buf)
+
+	entry := testobj.Entries[0]
+	entry.Attachments = map[string][]byte{"dump": []byte("stack")}
+	backend.Log(&entry)
+
+	out := LogEntry{}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, out.Attachments, entry.Attachments)
+}
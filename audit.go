@@ -0,0 +1,156 @@
+package lumberjack
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+//AuditBackend implements a Backend that writes a tamper-evident audit log
+//of newline delimited JSON records to an io.Writer. Each record holds a
+//sequence number, the LogEntry, and a hash chaining it to the previous record,
+//computed as SHA-256(previous hash || sequence number and LogEntry), so that
+//altered, removed, or reordered records are detected by VerifyAuditLog.
+//
+//Records removed from the end of the audit log leave a valid chain behind, so
+//they can't be detected from the audit log alone. To detect them, store the
+//sequence number and hash of the last record, as returned by Head, somewhere
+//the audit log's writer can't alter, and compare them to the last record.
+type AuditBackend struct {
+	writer io.Writer
+	seq    uint64
+	prev   []byte
+	sync.Mutex
+}
+
+//auditRecord is the structure of a single record written by an AuditBackend.
+type auditRecord struct {
+	Seq   uint64          `json:"seq"`
+	Entry json.RawMessage `json:"entry"`
+	Hash  string          `json:"hash"`
+}
+
+//NewAuditBackend returns an instance of AuditBackend that writes the audit
+//log to the specified io.Writer, starting a new chain.
+func NewAuditBackend(w io.Writer) *AuditBackend {
+	return &AuditBackend{writer: w}
+}
+
+//ResumeAuditBackend returns an instance of AuditBackend that continues the
+//chain of the existing audit log read from the specified io.Reader, writing
+//the new records to the specified io.Writer, such as the same file opened for
+//appending after a restart. The existing audit log is verified first, so the
+//new records never chain to altered ones, and an error is returned if it
+//fails verification.
+func ResumeAuditBackend(r io.Reader, w io.Writer) (*AuditBackend, error) {
+	seq, prev, err := verifyAuditChain(r)
+	if err != nil {
+		return nil, err
+	}
+	return &AuditBackend{writer: w, seq: seq, prev: prev}, nil
+}
+
+//Head returns the sequence number and hash of the last record written by the
+//current AuditBackend, or 0 and an empty string if none has been written.
+func (b *AuditBackend) Head() (uint64, string) {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.prev == nil {
+		return 0, ""
+	}
+	return b.seq, hex.EncodeToString(b.prev)
+}
+
+//Log satisfies the Backend interfaces requirements used for accepting
+//LogEntry objects to write out to the audit log.
+func (b *AuditBackend) Log(entry *LogEntry) {
+	data, err := marshalEntry(entry, false)
+	if err != nil {
+		logInternalf(ERROR, "Audit Backend: unable to Marshal JSON from LogEntry: %s", err)
+		return
+	}
+
+	b.Lock()
+	defer b.Unlock()
+
+	seq := b.seq + 1
+	hash := auditHash(b.prev, seq, data)
+
+	record, err := json.Marshal(auditRecord{Seq: seq, Entry: data, Hash: hex.EncodeToString(hash)})
+	if err != nil {
+		logInternalf(ERROR, "Audit Backend: unable to Marshal JSON from audit record: %s", err)
+		return
+	}
+
+	if _, err := b.writer.Write(append(record, '\n')); err != nil {
+		logInternalf(ERROR, "Audit Backend: unable to write audit record: %s", err)
+		return
+	}
+
+	b.seq = seq
+	b.prev = hash
+}
+
+//VerifyAuditLog reads an audit log written by an AuditBackend from the
+//specified io.Reader and verifies that the sequence numbers are contiguous
+//and that every hash chains to the previous record. An error describing the
+//first record that fails verification is returned, if any. Records removed
+//from the end are not detected, see AuditBackend.
+func VerifyAuditLog(r io.Reader) error {
+	_, _, err := verifyAuditChain(r)
+	return err
+}
+
+//verifyAuditChain is an internal function that verifies the audit log read
+//from the specified io.Reader as VerifyAuditLog does, returning the sequence
+//number and hash of the last record.
+func verifyAuditChain(r io.Reader) (uint64, []byte, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	var prev []byte
+	var seq uint64
+
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var record auditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return 0, nil, fmt.Errorf("Audit record after sequence %d is malformed: %s", seq, err)
+		}
+
+		if record.Seq != seq+1 {
+			return 0, nil, fmt.Errorf("Audit record sequence %d follows %d, records are missing or reordered", record.Seq, seq)
+		}
+
+		hash := auditHash(prev, record.Seq, record.Entry)
+		if hex.EncodeToString(hash) != record.Hash {
+			return 0, nil, fmt.Errorf("Audit record sequence %d fails hash verification", record.Seq)
+		}
+
+		prev = hash
+		seq = record.Seq
+	}
+
+	if err := scanner.Err(); err != nil {
+		return 0, nil, err
+	}
+	return seq, prev, nil
+}
+
+//auditHash computes the hash of an audit record from the hash of the
+//previous record, the sequence number, and the Marshalled LogEntry.
+func auditHash(prev []byte, seq uint64, entry []byte) []byte {
+	h := sha256.New()
+	h.Write(prev)
+	fmt.Fprintf(h, "%d:", seq)
+	h.Write(entry)
+	return h.Sum(nil)
+}
@@ -0,0 +1,106 @@
+package lumberjack
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+//auditLog writes the specified messages to a new AuditBackend and returns
+//the lines of the audit log.
+func auditLog(t *testing.T, messages ...string) []string {
+	var buf bytes.Buffer
+
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+	logger.AddBackend("audit", NewAuditBackend(&buf))
+
+	for _, message := range messages {
+		logger.Info(message)
+	}
+
+	return strings.Split(strings.TrimSpace(buf.String()), "\n")
+}
+
+func TestVerifyAuditLog(t *testing.T) {
+	lines := auditLog(t, "one", "two", "three")
+	expect(t, len(lines), 3)
+
+	if err := VerifyAuditLog(strings.NewReader(strings.Join(lines, "\n"))); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerifyAuditLogAltered(t *testing.T) {
+	lines := auditLog(t, "one", "two", "three")
+	lines[1] = strings.Replace(lines[1], `"message":"two"`, `"message":"TWO"`, 1)
+
+	err := VerifyAuditLog(strings.NewReader(strings.Join(lines, "\n")))
+	if err == nil {
+		t.Fatal("Expected error for altered audit record")
+	}
+	expect(t, err.Error(), "Audit record sequence 2 fails hash verification")
+}
+
+func TestVerifyAuditLogRemoved(t *testing.T) {
+	lines := auditLog(t, "one", "two", "three")
+	lines = append(lines[:1], lines[2:]...)
+
+	err := VerifyAuditLog(strings.NewReader(strings.Join(lines, "\n")))
+	if err == nil {
+		t.Fatal("Expected error for removed audit record")
+	}
+	expect(t, err.Error(), "Audit record sequence 3 follows 1, records are missing or reordered")
+}
+
+func TestResumeAuditBackend(t *testing.T) {
+	var buf bytes.Buffer
+
+	first := NewAuditBackend(&buf)
+	entry := testobj.Entries[0]
+	first.Log(&entry)
+	first.Log(&entry)
+	seq, hash := first.Head()
+	expect(t, seq, uint64(2))
+
+	//Simulate a restart by resuming from what was written so far.
+	resumed, err := ResumeAuditBackend(bytes.NewReader(buf.Bytes()), &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resumedSeq, resumedHash := resumed.Head()
+	expect(t, resumedSeq, seq)
+	expect(t, resumedHash, hash)
+
+	resumed.Log(&entry)
+	resumedSeq, _ = resumed.Head()
+	expect(t, resumedSeq, uint64(3))
+
+	if err := VerifyAuditLog(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResumeAuditBackendAltered(t *testing.T) {
+	lines := auditLog(t, "one", "two")
+	lines[0] = strings.Replace(lines[0], `"message":"one"`, `"message":"ONE"`, 1)
+
+	if _, err := ResumeAuditBackend(strings.NewReader(strings.Join(lines, "\n")), &bytes.Buffer{}); err == nil {
+		t.Fatal("Expected error resuming from an altered audit log")
+	}
+}
+
+func TestAuditBackendHeadEmpty(t *testing.T) {
+	seq, hash := NewAuditBackend(&bytes.Buffer{}).Head()
+	expect(t, seq, uint64(0))
+	expect(t, hash, "")
+
+	//Resuming from an empty audit log starts a new chain.
+	resumed, err := ResumeAuditBackend(strings.NewReader(""), &bytes.Buffer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	seq, hash = resumed.Head()
+	expect(t, seq, uint64(0))
+	expect(t, hash, "")
+}
@@ -1,7 +1,39 @@
 package lumberjack
 
+import "time"
+
 //Backend is an interface that must be implemented
 //in order to be utilized by an instance of Logger.
 type Backend interface {
 	Log(*LogEntry)
 }
+
+//Flusher is an optional interface that may be implemented by a Backend
+//that buffers LogEntry objects, allowing for the buffer to be written
+//out on demand.
+type Flusher interface {
+	Flush() error
+}
+
+//Drainer is an optional interface that may be implemented by a Backend
+//with a backlog of LogEntry objects, such as a network backend, allowing
+//for the backlog to be delivered and the Backend closed by a deadline.
+type Drainer interface {
+	Drain(deadline time.Time) error
+}
+
+//ErrorLogger is an optional interface that may be implemented by a Backend
+//that writes each LogEntry out as it is logged, such as the FileBackend,
+//allowing for the error writing a LogEntry to be reported to the caller
+//rather than only logged internally.
+type ErrorLogger interface {
+	LogErr(*LogEntry) error
+}
+
+//Dropper is an optional interface that may be implemented by a Backend
+//that drops LogEntry objects it can't keep up with, such as the
+//HttpClientBackend and TCPBackend, allowing for the number dropped to be
+//counted in the Summary of the Logger.
+type Dropper interface {
+	Dropped() uint64
+}
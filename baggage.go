@@ -0,0 +1,79 @@
+package lumberjack
+
+import "context"
+
+//BaggageLookupFunc returns the value of the baggage member with the specified
+//key held by the specified context.Context, and whether it holds one. A lookup
+//for OpenTelemetry baggage can be written with baggage.FromContext, so that
+//tracing stays an optional dependency.
+type BaggageLookupFunc func(ctx context.Context, key string) (string, bool)
+
+//baggageKey is the context key used to hold the baggage set with
+//ContextWithBaggage.
+type baggageKey struct{}
+
+//ContextWithBaggage returns a copy of the specified context.Context holding
+//the specified baggage member along with any it already holds, for use with
+//PropagateBaggage when no tracing library is in use.
+func ContextWithBaggage(ctx context.Context, key, value string) context.Context {
+	existing, _ := ctx.Value(baggageKey{}).(map[string]string)
+	baggage := make(map[string]string, len(existing)+1)
+	for k, v := range existing {
+		baggage[k] = v
+	}
+	baggage[key] = value
+	return context.WithValue(ctx, baggageKey{}, baggage)
+}
+
+//BaggageFromContext returns the value of the baggage member with the specified
+//key set with ContextWithBaggage, and whether the context.Context holds one. It
+//is the default BaggageLookupFunc.
+func BaggageFromContext(ctx context.Context, key string) (string, bool) {
+	baggage, _ := ctx.Value(baggageKey{}).(map[string]string)
+	value, exists := baggage[key]
+	return value, exists
+}
+
+//PropagateBaggage sets the keys of the baggage members copied into the Fields
+//of every LogEntry sent by a Logger derived with WithContext, replacing any
+//keys set before. Fields already set on the LogEntry are not overwritten.
+func (l *Logger) PropagateBaggage(keys ...string) {
+	l.Lock()
+	defer l.Unlock()
+	l.baggageKeys = append([]string(nil), keys...)
+}
+
+//SetBaggageLookup sets the function used by PropagateBaggage to look up the
+//baggage of a context.Context. A nil function restores the default of
+//BaggageFromContext.
+func (l *Logger) SetBaggageLookup(lookup BaggageLookupFunc) {
+	l.Lock()
+	defer l.Unlock()
+	l.baggageLookup = lookup
+}
+
+//applyBaggage is an internal method that copies the propagated baggage of the
+//context.Context of the specified LogEntry into its Fields.
+func (l *Logger) applyBaggage(entry *LogEntry) {
+	if entry.Context == nil {
+		return
+	}
+
+	l.Lock()
+	keys := l.baggageKeys
+	lookup := l.baggageLookup
+	l.Unlock()
+
+	if lookup == nil {
+		lookup = BaggageFromContext
+	}
+
+	for _, key := range keys {
+		if _, exists := entry.Fields[key]; exists {
+			continue
+		}
+		if value, exists := lookup(entry.Context, key); exists {
+			entry.SetField(key, value)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package lumberjack
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPropagateBaggage(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	logger.PropagateBaggage("tenant", "region")
+
+	ctx := ContextWithBaggage(context.Background(), "tenant", "acme")
+	ctx = ContextWithBaggage(ctx, "session", "secret")
+
+	logger.WithContext(ctx).Info("with baggage")
+	logger.WithContext(ctx).WithField("tenant", "override").Info("field wins")
+	logger.Info("no context")
+
+	entries := capture.Entries()
+	expect(t, len(entries), 3)
+	expect(t, entries[0].Fields, map[string]interface{}{"tenant": "acme"})
+	expect(t, entries[1].Fields, map[string]interface{}{"tenant": "override"})
+	expect(t, len(entries[2].Fields), 0)
+}
+
+func TestSetBaggageLookup(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	logger.PropagateBaggage("user")
+	logger.SetBaggageLookup(func(ctx context.Context, key string) (string, bool) {
+		return "from-" + key, true
+	})
+
+	logger.WithContext(context.Background()).Info("custom")
+
+	entries := capture.Entries()
+	expect(t, len(entries), 1)
+	expect(t, entries[0].Fields, map[string]interface{}{"user": "from-user"})
+}
@@ -0,0 +1,81 @@
+package lumberjack
+
+import "fmt"
+
+//bootstrapBuffer holds the LogEntry objects sent by a Logger before any
+//Backend is added to it, up to a capacity.
+type bootstrapBuffer struct {
+	capacity int
+	entries  []LogEntry
+	dropped  int
+}
+
+//EnableBootstrapBuffer enables holding up to the specified capacity of
+//LogEntry objects sent by the current Logger before any Backend is added to
+//it, such as while the configuration is being loaded at startup, rather than
+//discarding them. Once the first Backend is added with AddBackend or
+//AddBackends, the held LogEntry objects are replayed to it and the buffer is
+//disabled. LogEntry objects beyond the capacity are dropped. An error is
+//returned if the capacity is not positive or a Backend is already added.
+func (l *Logger) EnableBootstrapBuffer(capacity int) error {
+	if capacity <= 0 {
+		return fmt.Errorf("Bootstrap buffer capacity must be positive: %d", capacity)
+	}
+
+	l.Lock()
+	defer l.Unlock()
+	if len(l.backends) > 0 {
+		return fmt.Errorf("Bootstrap buffer can't be enabled once Backends are added")
+	}
+	l.bootstrap = &bootstrapBuffer{capacity: capacity}
+	return nil
+}
+
+//holdBootstrap is an internal method that holds a copy of the specified
+//LogEntry in the bootstrap buffer if it is enabled and no Backend is added,
+//returning true if the bootstrap buffer took it. The caller must hold the
+//lock.
+func (l *Logger) holdBootstrap(entry *LogEntry) bool {
+	if l.bootstrap == nil || len(l.backends) > 0 {
+		return false
+	}
+
+	if len(l.bootstrap.entries) < l.bootstrap.capacity {
+		l.bootstrap.entries = append(l.bootstrap.entries, *entry)
+	} else {
+		l.bootstrap.dropped++
+	}
+	return true
+}
+
+//takeBootstrap is an internal method that detaches the bootstrap buffer from
+//the current Logger once a Backend is added to it, disabling the buffer, and
+//returns it to be replayed with replayBootstrap, or nil if there is nothing to
+//replay. The caller must hold the lock.
+func (l *Logger) takeBootstrap() *bootstrapBuffer {
+	buffer := l.bootstrap
+	if buffer == nil || len(l.backends) == 0 {
+		return nil
+	}
+	l.bootstrap = nil
+	return buffer
+}
+
+//replayBootstrap is an internal method that replays the LogEntry objects held
+//in the specified bootstrap buffer to the backends of the current Logger, in
+//the same way as any other LogEntry, so that routes and disabled backends are
+//honored and the backends are called outside the lock. The caller must not
+//hold the lock.
+func (l *Logger) replayBootstrap(buffer *bootstrapBuffer) {
+	if buffer == nil {
+		return
+	}
+
+	if buffer.dropped > 0 {
+		logInternalf(WARN, "Bootstrap buffer was full, %d LogEntry objects logged before a Backend was added were dropped", buffer.dropped)
+	}
+
+	for i := range buffer.entries {
+		l.sendToBackends(&buffer.entries[i])
+	}
+}
@@ -0,0 +1,106 @@
+package lumberjack
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBootstrapBuffer(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+
+	if err := logger.EnableBootstrapBuffer(2); err != nil {
+		t.Fatal(err)
+	}
+
+	logger.Info("loading config")
+	logger.Info("config loaded")
+	logger.Info("dropped")
+
+	first := &captureBackend{}
+	second := &captureBackend{}
+	if err := logger.AddBackends(map[string]Backend{"first": first, "second": second}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, capture := range []*captureBackend{first, second} {
+		entries := capture.Entries()
+		expect(t, len(entries), 2)
+		expect(t, entries[0].Message, "loading config")
+		expect(t, entries[1].Message, "config loaded")
+	}
+
+	// Once replayed, the buffer is disabled and later backends get nothing old.
+	logger.Info("running")
+	late := &captureBackend{}
+	logger.AddBackend("late", late)
+	expect(t, len(first.Entries()), 3)
+	expect(t, len(late.Entries()), 0)
+}
+
+func TestBootstrapBufferAddBackend(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+	logger.EnableBootstrapBuffer(10)
+
+	logger.Info("early")
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+	logger.Info("later")
+
+	entries := capture.Entries()
+	expect(t, len(entries), 2)
+	expect(t, entries[0].Message, "early")
+	expect(t, entries[1].Message, "later")
+}
+
+func TestBootstrapBufferInvalid(t *testing.T) {
+	logger := NewLogger()
+	if err := logger.EnableBootstrapBuffer(0); err == nil {
+		t.Error("Expected an error enabling a bootstrap buffer without capacity")
+	}
+
+	logger.AddBackend("capture", &captureBackend{})
+	if err := logger.EnableBootstrapBuffer(10); err == nil {
+		t.Error("Expected an error enabling a bootstrap buffer with a Backend added")
+	}
+}
+
+//lockingBackend is a Backend that reads the backends of its Logger as it
+//receives each LogEntry, which takes the Logger lock.
+type lockingBackend struct {
+	logger *Logger
+	captureBackend
+}
+
+func (b *lockingBackend) Log(entry *LogEntry) {
+	b.logger.GetBackend("locking")
+	b.captureBackend.Log(entry)
+}
+
+func TestBootstrapBufferReplaysOutsideLock(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+	logger.EnableBootstrapBuffer(10)
+
+	logger.Info("early")
+
+	//Would deadlock if the bootstrap buffer was replayed under the lock.
+	backend := &lockingBackend{logger: logger}
+	done := make(chan struct{})
+	go func() {
+		logger.AddBackend("locking", backend)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 5):
+		t.Fatal("Timed out replaying the bootstrap buffer")
+	}
+
+	entries := backend.Entries()
+	expect(t, len(entries), 1)
+	expect(t, entries[0].Message, "early")
+}
@@ -0,0 +1,168 @@
+package lumberjack
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+//CallSite holds the source information of a call to log, captured once with
+//CaptureCaller so that it can be reused by hot paths that log repeatedly from
+//the same place, skipping the cost of looking it up on every call.
+type CallSite struct {
+	caller string
+	path   string
+	file   string
+	line   int
+}
+
+//CaptureCaller returns the CallSite of the function calling it, to be passed
+//to the logging methods ending in At, such as InfoAt.
+func CaptureCaller() CallSite {
+	return captureCallSite(2)
+}
+
+//CaptureCallerSkip returns the CallSite of the caller the specified number of
+//frames above the function calling it, such as for an adapter to another
+//logging package to report the caller of the adapter. A skip of 0 is the same
+//as CaptureCaller.
+func CaptureCallerSkip(skip int) CallSite {
+	return captureCallSite(2 + skip)
+}
+
+//captureCallSite uses the Go runtime to determine the name of the source
+//file, line number, and function block of the caller the specified number
+//of frames up the stack.
+func captureCallSite(skip int) CallSite {
+	pc, file, line, ok := runtime.Caller(skip)
+	if !ok {
+		return CallSite{caller: "???", file: "???"}
+	}
+
+	cs := CallSite{caller: "???", line: line}
+	if f := runtime.FuncForPC(pc); f != nil {
+		cs.caller = f.Name()
+	}
+	cs.path, cs.file = filepath.Split(file)
+	return cs
+}
+
+//callSiteForPC uses the Go runtime to determine the name of the source
+//file, line number, and function block of the specified program counter,
+//such as one recorded by another logging package.
+func callSiteForPC(pc uintptr) CallSite {
+	if pc == 0 {
+		return CallSite{caller: "???", file: "???"}
+	}
+
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	cs := CallSite{caller: frame.Function, line: frame.Line}
+	if cs.caller == "" {
+		cs.caller = "???"
+	}
+	cs.path, cs.file = filepath.Split(frame.File)
+	return cs
+}
+
+//SetRawCallerNames sets whether the caller of each LogEntry sent by the
+//current Logger keeps the raw function name reported by the Go runtime. By
+//default, the type arguments of generic functions and types are removed, so
+//that "pkg.Map[go.shape.int]" is simplified to "pkg.Map".
+func (l *Logger) SetRawCallerNames(raw bool) {
+	l.Lock()
+	defer l.Unlock()
+	l.rawCallers = raw
+}
+
+//simplifyCaller removes every bracketed list of type arguments from the
+//specified function name, including nested ones, so that the instantiations
+//of generic functions and types are reported as their plain names.
+func simplifyCaller(name string) string {
+	if strings.IndexByte(name, '[') < 0 {
+		return name
+	}
+
+	var b strings.Builder
+	depth := 0
+	for _, r := range name {
+		switch {
+		case r == '[':
+			depth++
+		case r == ']' && depth > 0:
+			depth--
+		case depth == 0:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+//entry returns a pointer to a LogEntry with the specified LogLevel and
+//message, the source information of the CallSite, and the current time.
+func (cs CallSite) entry(level LogLevel, message string) *LogEntry {
+	return &LogEntry{
+		Time:    time.Now(),
+		Level:   level,
+		Path:    cs.path,
+		File:    cs.file,
+		Line:    cs.line,
+		Caller:  cs.caller,
+		Message: message,
+	}
+}
+
+//InfoAt logs a string built from the specified args with the source
+//information of the specified CallSite if the INFO LogLevel is currently
+//added to the Logger.
+func (l *Logger) InfoAt(cs CallSite, args ...interface{}) {
+	if _, ok := l.begin(INFO); ok {
+		l.dispatch(cs.entry(INFO, fmt.Sprint(args...)))
+	}
+}
+
+//WarnAt logs a string built from the specified args with the source
+//information of the specified CallSite if the WARN LogLevel is currently
+//added to the Logger.
+func (l *Logger) WarnAt(cs CallSite, args ...interface{}) {
+	if _, ok := l.begin(WARN); ok {
+		l.dispatch(cs.entry(WARN, fmt.Sprint(args...)))
+	}
+}
+
+//ErrorAt logs a string built from the specified args with the source
+//information of the specified CallSite if the ERROR LogLevel is currently
+//added to the Logger.
+func (l *Logger) ErrorAt(cs CallSite, args ...interface{}) {
+	if _, ok := l.begin(ERROR); ok {
+		l.dispatch(cs.entry(ERROR, fmt.Sprint(args...)))
+	}
+}
+
+//CriticalAt logs a string built from the specified args with the source
+//information of the specified CallSite if the CRITICAL LogLevel is currently
+//added to the Logger.
+func (l *Logger) CriticalAt(cs CallSite, args ...interface{}) {
+	if _, ok := l.begin(CRITICAL); ok {
+		l.dispatch(cs.entry(CRITICAL, fmt.Sprint(args...)))
+	}
+}
+
+//DebugAt logs a string built from the specified args with the source
+//information of the specified CallSite if the DEBUG LogLevel is currently
+//added to the Logger.
+func (l *Logger) DebugAt(cs CallSite, args ...interface{}) {
+	if _, ok := l.begin(DEBUG); ok {
+		l.dispatch(cs.entry(DEBUG, fmt.Sprint(args...)))
+	}
+}
+
+//TraceAt logs a string built from the specified args with the source
+//information of the specified CallSite if the TRACE LogLevel is currently
+//added to the Logger.
+func (l *Logger) TraceAt(cs CallSite, args ...interface{}) {
+	if _, ok := l.begin(TRACE); ok {
+		l.dispatch(cs.entry(TRACE, fmt.Sprint(args...)))
+	}
+}
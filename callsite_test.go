@@ -0,0 +1,136 @@
+package lumberjack
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestInfoAt(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	cs := CaptureCaller()
+	_, _, line, _ := runtime.Caller(0)
+	for i := 0; i < 3; i++ {
+		logger.InfoAt(cs, "looping")
+	}
+	logger.Info("direct")
+
+	entries := capture.Entries()
+	expect(t, len(entries), 4)
+
+	for _, entry := range entries[:3] {
+		expect(t, entry.Caller, "github.com/btnmasher/lumberjack.TestInfoAt")
+		expect(t, entry.File, "callsite_test.go")
+		expect(t, entry.Line, line-1)
+		expect(t, entry.Message, "looping")
+	}
+
+	//The reused CallSite matches what a direct call captures, aside from the line.
+	expect(t, entries[3].Caller, entries[0].Caller)
+	expect(t, entries[3].Path, entries[0].Path)
+	expect(t, entries[3].File, entries[0].File)
+}
+
+func BenchmarkInfo(b *testing.B) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+	logger.AddBackend("discard", DiscardBackend{})
+
+	for i := 0; i < b.N; i++ {
+		logger.Info("benchmark")
+	}
+}
+
+func BenchmarkInfoAt(b *testing.B) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+	logger.AddBackend("discard", DiscardBackend{})
+
+	cs := CaptureCaller()
+	for i := 0; i < b.N; i++ {
+		logger.InfoAt(cs, "benchmark")
+	}
+}
+
+func TestSimplifyCaller(t *testing.T) {
+	cases := map[string]string{
+		"main.main":             "main.main",
+		"pkg.Map[...]":          "pkg.Map",
+		"pkg.Map[go.shape.int]": "pkg.Map",
+		"pkg.Map[go.shape.map[string]int,go.shape.int]": "pkg.Map",
+		"pkg.(*List[go.shape.string]).Push":             "pkg.(*List).Push",
+		"pkg.Map[...].func1":                            "pkg.Map.func1",
+	}
+
+	for name, want := range cases {
+		expect(t, simplifyCaller(name), want)
+	}
+}
+
+func TestRawCallerNames(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	cs := CaptureCaller()
+	cs.caller = "pkg.Map[go.shape.int]"
+
+	logger.InfoAt(cs, "simplified")
+	logger.SetRawCallerNames(true)
+	logger.InfoAt(cs, "raw")
+
+	entries := capture.Entries()
+	expect(t, entries[0].Caller, "pkg.Map")
+	expect(t, entries[1].Caller, "pkg.Map[go.shape.int]")
+}
+
+//logHelper is a wrapper around Info, as an application might write.
+func logHelper(logger *Logger, message string) {
+	logger.Info(message)
+}
+
+func TestSetCallerSkip(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	logHelper(logger, "unskipped")
+
+	if err := logger.SetCallerSkip(1); err != nil {
+		t.Fatal(err)
+	}
+	_, _, line, _ := runtime.Caller(0)
+	logHelper(logger, "skipped")
+
+	entries := capture.Entries()
+	expect(t, entries[0].Caller, "github.com/btnmasher/lumberjack.logHelper")
+	expect(t, entries[1].Caller, "github.com/btnmasher/lumberjack.TestSetCallerSkip")
+	expect(t, entries[1].File, "callsite_test.go")
+	expect(t, entries[1].Line, line+1)
+
+	if err := logger.SetCallerSkip(-1); err == nil {
+		t.Error("Expected error for negative caller skip")
+	}
+}
+
+//captureHelperCaller returns the CallSite of its caller, as an adapter would.
+func captureHelperCaller() CallSite {
+	return CaptureCallerSkip(1)
+}
+
+func TestCaptureCallerSkip(t *testing.T) {
+	_, _, line, _ := runtime.Caller(0)
+	cs := captureHelperCaller()
+
+	expect(t, cs.caller, "github.com/btnmasher/lumberjack.TestCaptureCallerSkip")
+	expect(t, cs.file, "callsite_test.go")
+	expect(t, cs.line, line+1)
+}
@@ -0,0 +1,56 @@
+package lumberjack
+
+//HandlerFunc handles a LogEntry sent by a Logger, such as by passing it on
+//to the next HandlerFunc in a chain of Middleware.
+type HandlerFunc func(entry *LogEntry)
+
+//Middleware wraps the next HandlerFunc of a chain with its own handling of
+//each LogEntry, such as redacting or adding fields before calling next, or
+//dropping the LogEntry by not calling next at all.
+type Middleware func(next HandlerFunc) HandlerFunc
+
+//Use adds the specified Middleware to the chain each LogEntry sent by the
+//current Logger passes through once the Logger's configuration, enrichers,
+//and FieldPolicy have been applied to it. The Middleware are called in the
+//order they are added, and the final HandlerFunc of the chain sends the
+//LogEntry on to the backends, subject to any coalescing and routing rules.
+//
+//The Middleware chain is shared by every child Logger of the current Logger,
+//and is called without the Logger locked, so Middleware may log.
+func (l *Logger) Use(middlewares ...Middleware) {
+	l.Lock()
+	defer l.Unlock()
+
+	//Copy so that a chain being run by another Goroutine is never modified.
+	chain := make([]Middleware, 0, len(l.middlewares)+len(middlewares))
+	chain = append(chain, l.middlewares...)
+	l.middlewares = append(chain, middlewares...)
+}
+
+//handle passes the specified LogEntry through the Middleware chain of the
+//current Logger, ending in the delivery of it to the backends.
+func (l *Logger) handle(entry *LogEntry) {
+	l.Lock()
+	middlewares := l.middlewares
+	l.Unlock()
+
+	handler := HandlerFunc(l.deliver)
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	handler(entry)
+}
+
+//deliver sends the specified LogEntry to the backends of the current Logger,
+//through the coalescer if one is set.
+func (l *Logger) deliver(entry *LogEntry) {
+	l.Lock()
+	c := l.coalescer
+	l.Unlock()
+
+	if c != nil {
+		c.add(entry)
+		return
+	}
+	l.sendToBackends(entry)
+}
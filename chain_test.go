@@ -0,0 +1,53 @@
+package lumberjack
+
+import "testing"
+
+func TestUse(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(DEBUG)
+	logger.AddLevel(INFO)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	var order []string
+	enricher := func(next HandlerFunc) HandlerFunc {
+		return func(entry *LogEntry) {
+			order = append(order, "enricher")
+			entry.SetField("service", "billing")
+			next(entry)
+		}
+	}
+	dropper := func(next HandlerFunc) HandlerFunc {
+		return func(entry *LogEntry) {
+			order = append(order, "dropper")
+			if entry.Level == DEBUG {
+				return
+			}
+			next(entry)
+		}
+	}
+	logger.Use(enricher, dropper)
+
+	logger.Debug("dropped")
+	logger.WithField("user", 42).Info("kept")
+
+	expect(t, order, []string{"enricher", "dropper", "enricher", "dropper"})
+
+	entries := capture.Entries()
+	expect(t, len(entries), 1)
+	expect(t, entries[0].Message, "kept")
+	expect(t, entries[0].Fields["service"], "billing")
+	expect(t, entries[0].Fields["user"], 42)
+}
+
+func TestUseWithoutMiddleware(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	logger.Info("sent")
+	expect(t, len(capture.Entries()), 1)
+}
@@ -0,0 +1,18 @@
+package lumberjack
+
+import "time"
+
+//Clock is an interface used to retrieve the current time. It allows for
+//time dependent behavior to be tested deterministically by substituting
+//a Clock that returns a controlled time.
+type Clock interface {
+	Now() time.Time
+}
+
+//systemClock implements the Clock interface using the system time.
+type systemClock struct{}
+
+//Now returns the current system time.
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,29 @@
+package lumberjack
+
+import (
+	"sync"
+	"time"
+)
+
+//fakeClock is a Clock used for testing that only moves forward in time
+//when advanced.
+type fakeClock struct {
+	now time.Time
+	sync.Mutex
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2015, 8, 17, 12, 23, 57, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.Lock()
+	defer c.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.Lock()
+	defer c.Unlock()
+	c.now = c.now.Add(d)
+}
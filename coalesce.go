@@ -0,0 +1,120 @@
+package lumberjack
+
+import (
+	"reflect"
+	"sync"
+	"time"
+)
+
+//coalescer collapses back-to-back identical LogEntry objects sent within a
+//window of time into a single LogEntry with a repeat count. The first LogEntry
+//is held until a different LogEntry is sent or the window elapses.
+type coalescer struct {
+	window  time.Duration
+	send    func(*LogEntry)
+	pending *LogEntry
+	timer   *time.Timer
+	sync.Mutex
+}
+
+//SetCoalesce sets the window of time within which back-to-back identical
+//LogEntry objects, with the same LogLevel, message, component, and fields,
+//are collapsed into a single LogEntry before being sent to the backends. The
+//collapsed LogEntry carries the number of times it was logged in its Repeat
+//field. Each LogEntry is delayed by up to the window while waiting for any
+//repeats. A window of 0 turns coalescing off, which is the default.
+func (l *Logger) SetCoalesce(window time.Duration) {
+	var c *coalescer
+	if window > 0 {
+		c = &coalescer{window: window, send: l.sendToBackends}
+	}
+
+	l.Lock()
+	old := l.coalescer
+	l.coalescer = c
+	l.Unlock()
+
+	if old != nil {
+		old.flush()
+	}
+}
+
+//add accepts a LogEntry to be sent to the backends, collapsing it into the
+//pending LogEntry if they are identical.
+func (c *coalescer) add(entry *LogEntry) {
+	c.Lock()
+
+	if c.pending != nil && identicalEntries(c.pending, entry) {
+		c.pending.Repeat++
+		c.Unlock()
+		return
+	}
+
+	previous := c.takePending()
+
+	entry.Repeat = 1
+	c.pending = entry
+	c.timer = time.AfterFunc(c.window, func() { c.expire(entry) })
+	c.Unlock()
+
+	c.sendEntry(previous)
+}
+
+//flush sends the pending LogEntry, if any, to the backends.
+func (c *coalescer) flush() {
+	c.Lock()
+	entry := c.takePending()
+	c.Unlock()
+
+	c.sendEntry(entry)
+}
+
+//expire sends the specified LogEntry to the backends once its window has
+//elapsed, unless it is no longer the pending LogEntry because it was already
+//sent, so that a timer that fired late can't send a newer LogEntry early.
+func (c *coalescer) expire(entry *LogEntry) {
+	c.Lock()
+	if c.pending != entry {
+		c.Unlock()
+		return
+	}
+	entry = c.takePending()
+	c.Unlock()
+
+	c.sendEntry(entry)
+}
+
+//takePending removes and returns the pending LogEntry, if any, stopping its
+//timer. The caller must hold the lock.
+func (c *coalescer) takePending() *LogEntry {
+	if c.pending == nil {
+		return nil
+	}
+
+	c.timer.Stop()
+
+	entry := c.pending
+	c.pending = nil
+	return entry
+}
+
+//sendEntry sends the specified LogEntry taken from pending, if any, to the
+//backends. It is called without holding the lock, as sending may block.
+func (c *coalescer) sendEntry(entry *LogEntry) {
+	if entry == nil {
+		return
+	}
+	if entry.Repeat == 1 {
+		entry.Repeat = 0 //Only report repeats for entries that were collapsed.
+	}
+	c.send(entry)
+}
+
+//identicalEntries checks if two LogEntry objects have the same LogLevel,
+//message, component, and fields and returns true or false based on that check.
+func identicalEntries(a, b *LogEntry) bool {
+	return a.Level == b.Level &&
+		a.Message == b.Message &&
+		a.Component == b.Component &&
+		reflect.DeepEqual(a.Fields, b.Fields)
+}
@@ -0,0 +1,109 @@
+package lumberjack
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCoalesce(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+	logger.AddLevel(ERROR)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	logger.SetCoalesce(time.Hour)
+
+	for i := 0; i < 5; i++ {
+		logger.Info("same")
+	}
+
+	// Nothing is sent until a different entry arrives.
+	expect(t, len(capture.Entries()), 0)
+
+	logger.Error("same") // Different level, not identical.
+	logger.Info("different")
+
+	// Turning coalescing off sends what is pending.
+	logger.SetCoalesce(0)
+
+	entries := capture.Entries()
+	expect(t, len(entries), 3)
+	expect(t, entries[0].Message, "same")
+	expect(t, entries[0].Repeat, 5)
+	expect(t, entries[1].Level, ERROR)
+	expect(t, entries[1].Repeat, 0)
+	expect(t, entries[2].Message, "different")
+	expect(t, entries[2].Repeat, 0)
+
+	// Without coalescing, entries are sent straight away.
+	logger.Info("same")
+	logger.Info("same")
+	expect(t, len(capture.Entries()), 5)
+}
+
+func TestCoalesceWindow(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	logger.SetCoalesce(10 * time.Millisecond)
+
+	logger.Info("same")
+	logger.Info("same")
+
+	// The pending entry is sent once the window elapses.
+	deadline := time.Now().Add(time.Second)
+	for len(capture.Entries()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	entries := capture.Entries()
+	expect(t, len(entries), 1)
+	expect(t, entries[0].Repeat, 2)
+}
+
+func TestCoalesceStaleTimer(t *testing.T) {
+	var sent []*LogEntry
+	c := &coalescer{window: time.Hour, send: func(entry *LogEntry) { sent = append(sent, entry) }}
+
+	first := &LogEntry{Level: INFO, Message: "first"}
+	c.add(first)
+	second := &LogEntry{Level: INFO, Message: "second"}
+	c.add(second)
+	expect(t, len(sent), 1)
+
+	// A timer for the first entry firing late leaves the second one pending.
+	c.expire(first)
+	expect(t, len(sent), 1)
+
+	c.expire(second)
+	expect(t, len(sent), 2)
+	expect(t, sent[1].Message, "second")
+}
+
+func TestCoalesceSendsOutsideLock(t *testing.T) {
+	var c *coalescer
+	c = &coalescer{window: time.Hour, send: func(entry *LogEntry) {
+		// Would deadlock if the coalescer lock was held while sending.
+		c.Lock()
+		c.Unlock()
+	}}
+
+	done := make(chan struct{})
+	go func() {
+		c.add(&LogEntry{Level: INFO, Message: "first"})
+		c.add(&LogEntry{Level: INFO, Message: "second"})
+		c.flush()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 5):
+		t.Fatal("Timed out sending coalesced entries")
+	}
+}
@@ -0,0 +1,44 @@
+package lumberjack
+
+import "fmt"
+
+//WithComponent returns a child Logger that shares the configuration of the
+//current Logger and tags every LogEntry it sends with the specified component
+//name. The LogLevels logged by the child Logger can be overridden for the
+//component with SetComponentLevel.
+func (l *Logger) WithComponent(component string) *Logger {
+	child := *l
+	child.component = component
+	return &child
+}
+
+//SetComponentLevel overrides the LogLevels logged for the specified component,
+//so that any LogEntry sent by a Logger with that component is logged if it is
+//at least as severe as the specified minimum LogLevel, regardless of the
+//LogLevels added to the Logger. This allows for a single component to log at
+//DEBUG while the rest of the application only logs WARN and above.
+func (l *Logger) SetComponentLevel(component string, minLevel LogLevel) error {
+	if !validLevel(minLevel) {
+		return fmt.Errorf("Invalid LogLevel: %d", minLevel)
+	}
+	l.Lock()
+	defer l.Unlock()
+	if l.componentLevels == nil {
+		l.componentLevels = map[string]LogLevel{}
+	}
+	l.componentLevels[component] = minLevel
+	return nil
+}
+
+//RemoveComponentLevel removes the LogLevel override for the specified
+//component, so that it is logged according to the LogLevels added to the
+//Logger again.
+func (l *Logger) RemoveComponentLevel(component string) error {
+	l.Lock()
+	defer l.Unlock()
+	if _, exists := l.componentLevels[component]; !exists {
+		return fmt.Errorf("Component LogLevel not set: %s", component)
+	}
+	delete(l.componentLevels, component)
+	return nil
+}
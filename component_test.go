@@ -0,0 +1,65 @@
+package lumberjack
+
+import "testing"
+
+func TestComponentLevel(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(WARN)
+	logger.AddLevel(ERROR)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	payments := logger.WithComponent("payments")
+	shipping := logger.WithComponent("shipping")
+
+	if err := logger.SetComponentLevel("payments", DEBUG); err != nil {
+		t.Fatal(err)
+	}
+
+	payments.Debug("payments debug")
+	shipping.Debug("shipping debug")
+	logger.Debug("root debug")
+	shipping.Warn("shipping warn")
+
+	entries := capture.Entries()
+	expect(t, len(entries), 2)
+	expect(t, entries[0].Message, "payments debug")
+	expect(t, entries[0].Component, "payments")
+	expect(t, entries[1].Message, "shipping warn")
+	expect(t, entries[1].Component, "shipping")
+}
+
+func TestComponentLevelMinimum(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	noisy := logger.WithComponent("noisy")
+	logger.SetComponentLevel("noisy", ERROR)
+
+	noisy.Info("filtered")
+	noisy.Critical("passed")
+	logger.Info("root info")
+
+	entries := capture.Entries()
+	expect(t, len(entries), 2)
+	expect(t, entries[0].Message, "passed")
+	expect(t, entries[1].Message, "root info")
+
+	// Removing the override falls back to the LogLevels of the Logger.
+	if err := logger.RemoveComponentLevel("noisy"); err != nil {
+		t.Fatal(err)
+	}
+	noisy.Info("unfiltered")
+	expect(t, len(capture.Entries()), 3)
+
+	if err := logger.RemoveComponentLevel("noisy"); err == nil {
+		t.Error("Expected an error removing a missing component override")
+	}
+	if err := logger.SetComponentLevel("noisy", LogLevel(200)); err == nil {
+		t.Error("Expected an error for an invalid LogLevel")
+	}
+}
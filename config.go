@@ -0,0 +1,216 @@
+package lumberjack
+
+import (
+	"sort"
+	"time"
+)
+
+//Config is a snapshot of the configuration of a Logger that can be used to
+//restore the Logger to that configuration at a later time. The LogLevels and
+//Backend names are exported for inspection, while the Backends themselves and
+//any other options are held by reference.
+//
+//Only the configuration is captured, not the running state of the Logger,
+//such as its counts, size statistics, or bootstrap buffer. The buckets of the
+//rate limits and the alert guard, and the counter of the sampler, start over
+//when restored.
+type Config struct {
+	Levels   []LogLevel `json:"levels"`
+	Backends []string   `json:"backends"`
+
+	backends        map[string]Backend
+	enrichers       map[LogLevel][]func(*LogEntry)
+	version         string
+	components      map[string]LogLevel
+	deployment      deploymentInfo
+	hostPID         *hostPID
+	disabled        map[string]struct{}
+	routes          []route
+	middlewares     []Middleware
+	sampleRate      float64
+	sampleEvery     uint64
+	sampleThreshold LogLevel
+	rateLimits      map[LogLevel]float64
+	alertGuard      *alertGuard
+	fieldPolicy     FieldPolicy
+	coalesce        time.Duration
+	timerLevel      LogLevel
+	printLevel      LogLevel
+	baggageKeys     []string
+	baggageLookup   BaggageLookupFunc
+	rawCallers      bool
+	callerSkip      int
+}
+
+//Snapshot returns a Config capturing the configuration currently set on the
+//Logger: its LogLevels, Backends, disabled Backends, routes, Middleware,
+//enrichers, version, component LogLevels, deployment information, sampling,
+//rate limits, alert guard, FieldPolicy, coalescing window, timer and print
+//LogLevels, baggage propagation, and caller options.
+func (l *Logger) Snapshot() Config {
+	l.Lock()
+	defer l.Unlock()
+
+	config := Config{
+		backends:  map[string]Backend{},
+		enrichers: map[LogLevel][]func(*LogEntry){},
+	}
+
+	for level := range l.logLevels {
+		config.Levels = append(config.Levels, level)
+	}
+	sort.Slice(config.Levels, func(i, j int) bool { return config.Levels[i] < config.Levels[j] })
+
+	for name, backend := range l.backends {
+		config.Backends = append(config.Backends, name)
+		config.backends[name] = backend
+	}
+	sort.Strings(config.Backends)
+
+	for level, enrichers := range l.enrichers {
+		config.enrichers[level] = append([]func(*LogEntry){}, enrichers...)
+	}
+
+	config.version = l.version
+	config.deployment = l.deployment
+	config.hostPID = l.hostPID
+
+	config.components = map[string]LogLevel{}
+	for component, level := range l.componentLevels {
+		config.components[component] = level
+	}
+
+	config.disabled = map[string]struct{}{}
+	for name := range l.disabled {
+		config.disabled[name] = struct{}{}
+	}
+
+	config.routes = append([]route(nil), l.routes...)
+	config.middlewares = append([]Middleware(nil), l.middlewares...)
+
+	config.sampleRate = l.sampleRate
+	if l.sampler != nil {
+		config.sampleEvery = l.sampler.every
+	}
+	config.sampleThreshold = l.sampleThreshold
+
+	config.rateLimits = map[LogLevel]float64{}
+	for level, limiter := range l.rateLimits {
+		config.rateLimits[level] = limiter.perSecond
+	}
+
+	if l.alertGuard != nil {
+		config.alertGuard = l.alertGuard.reset()
+	}
+
+	config.fieldPolicy = l.fieldPolicy
+	config.coalesce = l.coalesceWindow()
+	config.timerLevel = l.timerLevel
+	config.printLevel = l.printLevel
+	config.baggageKeys = append([]string(nil), l.baggageKeys...)
+	config.baggageLookup = l.baggageLookup
+	config.rawCallers = l.rawCallers
+	config.callerSkip = l.callerSkip
+
+	return config
+}
+
+//Restore replaces the configuration currently set on the Logger with the one
+//captured in the specified Config by Snapshot. Any routes to Backends that
+//are not in the Config are dropped.
+func (l *Logger) Restore(config Config) {
+	l.Lock()
+
+	l.logLevels = map[LogLevel]struct{}{}
+	l.levelsShared = false
+	for _, level := range config.Levels {
+		l.logLevels[level] = struct{}{}
+	}
+
+	l.backends = map[string]Backend{}
+	for name, backend := range config.backends {
+		l.backends[name] = backend
+	}
+
+	l.enrichers = map[LogLevel][]func(*LogEntry){}
+	for level, enrichers := range config.enrichers {
+		l.enrichers[level] = append([]func(*LogEntry){}, enrichers...)
+	}
+
+	l.version = config.version
+	l.deployment = config.deployment
+	l.hostPID = config.hostPID
+
+	l.componentLevels = map[string]LogLevel{}
+	for component, level := range config.components {
+		l.componentLevels[component] = level
+	}
+
+	l.disabled = map[string]struct{}{}
+	for name := range config.disabled {
+		l.disabled[name] = struct{}{}
+	}
+
+	l.routes = nil
+	for _, r := range config.routes {
+		if _, exists := l.backends[r.backend]; exists {
+			l.routes = append(l.routes, r)
+		}
+	}
+	l.middlewares = append([]Middleware(nil), config.middlewares...)
+
+	l.sampleRate = config.sampleRate
+	l.sampler = nil
+	if config.sampleEvery > 1 {
+		l.sampler = &counterSampler{every: config.sampleEvery}
+	}
+	l.sampleThreshold = config.sampleThreshold
+
+	//Rate limits that are unchanged keep their bucket, so that the count of
+	//suppressed LogEntry objects waiting to be summarized isn't lost.
+	rateLimits := map[LogLevel]*rateLimiter{}
+	for level, perSecond := range config.rateLimits {
+		if limiter, exists := l.rateLimits[level]; exists && limiter.perSecond == perSecond {
+			rateLimits[level] = limiter
+			continue
+		}
+		rateLimits[level] = newRateLimiter(perSecond)
+	}
+	l.rateLimits = rateLimits
+
+	l.alertGuard = nil
+	if config.alertGuard != nil {
+		l.alertGuard = config.alertGuard.reset()
+	}
+
+	l.fieldPolicy = config.fieldPolicy
+	l.timerLevel = config.timerLevel
+	l.printLevel = config.printLevel
+	l.baggageKeys = append([]string(nil), config.baggageKeys...)
+	l.baggageLookup = config.baggageLookup
+	l.rawCallers = config.rawCallers
+	l.callerSkip = config.callerSkip
+
+	var old *coalescer
+	if window := l.coalesceWindow(); window != config.coalesce {
+		old = l.coalescer
+		l.coalescer = nil
+		if config.coalesce > 0 {
+			l.coalescer = &coalescer{window: config.coalesce, send: l.sendToBackends}
+		}
+	}
+	l.Unlock()
+
+	if old != nil {
+		old.flush()
+	}
+}
+
+//coalesceWindow returns the coalescing window of the current Logger, or 0 if
+//coalescing is off. The caller must hold the lock.
+func (l *Logger) coalesceWindow() time.Duration {
+	if l.coalescer == nil {
+		return 0
+	}
+	return l.coalescer.window
+}
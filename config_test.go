@@ -0,0 +1,116 @@
+package lumberjack
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+	logger.AddLevel(ERROR)
+
+	original := &captureBackend{}
+	logger.AddBackend("original", original)
+
+	config := logger.Snapshot()
+	expect(t, config.Levels, []LogLevel{INFO, ERROR})
+	expect(t, config.Backends, []string{"original"})
+
+	// Mutate the configuration.
+	logger.RemoveLevel(INFO)
+	logger.AddLevel(DEBUG)
+	logger.RemoveBackend("original")
+	replacement := &captureBackend{}
+	logger.AddBackend("replacement", replacement)
+	logger.AddLevelEnricher(ERROR, func(entry *LogEntry) {
+		entry.SetField("enriched", true)
+	})
+
+	logger.Restore(config)
+
+	expect(t, logger.levelSet(INFO), true)
+	expect(t, logger.levelSet(ERROR), true)
+	expect(t, logger.levelSet(DEBUG), false)
+	expect(t, logger.backendAdded("original"), true)
+	expect(t, logger.backendAdded("replacement"), false)
+
+	logger.Error("Test Error")
+
+	// Only the original backend should receive the entry, without enrichment.
+	entries := original.Entries()
+	expect(t, len(entries), 1)
+	expect(t, len(replacement.Entries()), 0)
+	_, exists := entries[0].Fields["enriched"]
+	expect(t, exists, false)
+
+	// The snapshot should be unaffected by changes after the restore.
+	logger.AddLevel(DEBUG)
+	expect(t, logger.Snapshot().Levels, []LogLevel{DEBUG, INFO, ERROR})
+	expect(t, config.Levels, []LogLevel{INFO, ERROR})
+}
+
+func TestSnapshotRestoreOptions(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+	logger.AddBackend("capture", &captureBackend{})
+
+	logger.Route(func(*LogEntry) bool { return true }, "capture", false)
+	logger.Use(func(next HandlerFunc) HandlerFunc { return next })
+	logger.SetSampleRate(0.5)
+	logger.SetSampler(10)
+	logger.SetSampleThreshold(ERROR)
+	logger.SetRateLimit(INFO, 100)
+	logger.SetAlertGuard(ERROR, 1, 5, "capture")
+	logger.SetFieldPolicy(FieldsCoerce)
+	logger.SetCoalesce(time.Second)
+	logger.SetCallerSkip(2)
+	logger.SetRawCallerNames(true)
+
+	config := logger.Snapshot()
+
+	// Mutate the configuration.
+	logger.ClearRoutes()
+	logger.Use(func(next HandlerFunc) HandlerFunc { return next })
+	logger.SetSampleRate(1)
+	logger.SetSampler(1)
+	logger.SetSampleThreshold(WARN)
+	logger.SetRateLimit(INFO, 0)
+	logger.RemoveAlertGuard()
+	logger.SetFieldPolicy(FieldsReject)
+	logger.SetCoalesce(0)
+	logger.SetCallerSkip(0)
+	logger.SetRawCallerNames(false)
+
+	logger.Restore(config)
+
+	expect(t, len(logger.routes), 1)
+	expect(t, len(logger.middlewares), 1)
+	expect(t, logger.sampleRate, 0.5)
+	expect(t, logger.sampler.every, uint64(10))
+	expect(t, logger.sampleThreshold, ERROR)
+	expect(t, logger.rateLimits[INFO].perSecond, float64(100))
+	expect(t, logger.alertGuard.guarded("capture"), true)
+	expect(t, logger.fieldPolicy, FieldsCoerce)
+	expect(t, logger.coalesceWindow(), time.Second)
+	expect(t, logger.callerSkip, 2)
+	expect(t, logger.rawCallers, true)
+
+	logger.SetCoalesce(0)
+}
+
+func TestRestoreDropsRoutesToMissingBackends(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+	logger.AddBackend("kept", &captureBackend{})
+	logger.AddBackend("removed", &captureBackend{})
+
+	logger.Route(func(*LogEntry) bool { return true }, "kept", false)
+	logger.Route(func(*LogEntry) bool { return true }, "removed", true)
+	logger.RemoveBackend("removed")
+
+	logger.Restore(logger.Snapshot())
+
+	expect(t, len(logger.routes), 1)
+	expect(t, logger.routes[0].backend, "kept")
+}
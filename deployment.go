@@ -0,0 +1,49 @@
+package lumberjack
+
+import "os"
+
+//deploymentInfo holds the metadata about the deployment an application is
+//running in that is attached to every LogEntry.
+type deploymentInfo struct {
+	env        string
+	region     string
+	instanceID string
+}
+
+//hostPID holds the hostname and process ID attached to every LogEntry once
+//EnableHostPID is called.
+type hostPID struct {
+	host string
+	pid  int
+}
+
+//SetDeploymentInfo sets the environment, region, and instance ID of the
+//deployment the application is running in, which are attached to every
+//LogEntry sent by the current Logger in the Env, Region, and InstanceID
+//fields. These are reserved fields so that backends with dedicated slots
+//for deployment metadata can map them directly. Empty values are left off.
+func (l *Logger) SetDeploymentInfo(env, region, instanceID string) {
+	l.Lock()
+	defer l.Unlock()
+	l.deployment = deploymentInfo{
+		env:        env,
+		region:     region,
+		instanceID: instanceID,
+	}
+}
+
+//EnableHostPID attaches the hostname of the machine and the process ID of the
+//application to every LogEntry sent by the current Logger in the Host and PID
+//fields, for telling apart the logs aggregated from several machines and
+//processes. The hostname is looked up once, when EnableHostPID is called, and
+//an error is logged internally if it can't be determined.
+func (l *Logger) EnableHostPID() {
+	host, err := os.Hostname()
+	if err != nil {
+		logInternalf(WARN, "Unable to determine hostname: %s", err)
+	}
+
+	l.Lock()
+	defer l.Unlock()
+	l.hostPID = &hostPID{host: host, pid: os.Getpid()}
+}
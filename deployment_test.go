@@ -0,0 +1,91 @@
+package lumberjack
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestSetDeploymentInfo(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newStructuredLogger(&buf)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	logger.SetDeploymentInfo("production", "us-east-1", "i-0abc123")
+	logger.Info("Test Info")
+
+	entries := capture.Entries()
+	expect(t, len(entries), 1)
+	expect(t, entries[0].Env, "production")
+	expect(t, entries[0].Region, "us-east-1")
+	expect(t, entries[0].InstanceID, "i-0abc123")
+
+	out := map[string]interface{}{}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, out["env"], "production")
+	expect(t, out["region"], "us-east-1")
+	expect(t, out["instance_id"], "i-0abc123")
+}
+
+func TestSetDeploymentInfoUnset(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newStructuredLogger(&buf)
+
+	logger.Info("Test Info")
+
+	out := map[string]interface{}{}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"env", "region", "instance_id"} {
+		if _, exists := out[key]; exists {
+			t.Errorf("Expected key %q to be omitted when unset", key)
+		}
+	}
+}
+
+func TestEnableHostPID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newStructuredLogger(&buf)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	logger.Info("before")
+	logger.EnableHostPID()
+	logger.Info("after")
+
+	host, err := os.Hostname()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries := capture.Entries()
+	expect(t, len(entries), 2)
+	expect(t, entries[0].Host, "")
+	expect(t, entries[0].PID, 0)
+	expect(t, entries[1].Host, host)
+	expect(t, entries[1].PID, os.Getpid())
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	expect(t, len(lines), 2)
+
+	out := map[string]interface{}{}
+	if err := json.Unmarshal(lines[0], &out); err != nil {
+		t.Fatal(err)
+	}
+	_, hasPID := out["pid"]
+	expect(t, hasPID, false)
+
+	out = map[string]interface{}{}
+	if err := json.Unmarshal(lines[1], &out); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, out["host"], host)
+	expect(t, out["pid"], float64(os.Getpid()))
+}
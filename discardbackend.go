@@ -0,0 +1,9 @@
+package lumberjack
+
+//DiscardBackend implements a Backend that discards every LogEntry sent to it,
+//such as for silencing a Logger in tests without removing its LogLevels, or
+//for benchmarking the cost of logging without the cost of any output.
+type DiscardBackend struct{}
+
+//Log satisfies the Backend interfaces requirements, discarding the LogEntry.
+func (DiscardBackend) Log(entry *LogEntry) {}
@@ -0,0 +1,37 @@
+package lumberjack
+
+import "testing"
+
+//DiscardBackend must satisfy the Backend interface.
+var _ Backend = DiscardBackend{}
+
+func TestDiscardBackend(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+	logger.AddBackend("discard", DiscardBackend{})
+
+	logger.Info("discarded")
+	expect(t, logger.Summary().Counts[INFO], uint64(1))
+}
+
+func BenchmarkInfofDiscard(b *testing.B) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+	logger.AddBackend("discard", DiscardBackend{})
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		logger.Infof("benchmark %d", i)
+	}
+}
+
+func BenchmarkDisabledLevelDiscard(b *testing.B) {
+	logger := NewLogger()
+	logger.AddLevel(ERROR)
+	logger.AddBackend("discard", DiscardBackend{})
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		logger.Infof("benchmark %d", i)
+	}
+}
@@ -0,0 +1,112 @@
+package lumberjack
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+//drainResult holds the outcome of draining a single Backend.
+type drainResult struct {
+	name string
+	err  error
+}
+
+//CloseWithTimeout logs the Summary of the current Logger if enabled with
+//EnableCloseSummary, then drains every Backend concurrently, waiting at most
+//the specified timeout. A Backend that implements the Drainer interface is
+//drained with the deadline, while any other is flushed if it implements the
+//Flusher interface and closed if it implements io.Closer.
+//
+//If any Backend did not finish draining in time, the returned error lists
+//their names, otherwise the first error encountered is returned, if any. Once
+//the Logger is closed, by either CloseWithTimeout or Close, calling either
+//again has no effect on the backends.
+func (l *Logger) CloseWithTimeout(timeout time.Duration) error {
+	l.Lock()
+	if l.closed {
+		l.Unlock()
+		return nil
+	}
+	l.closed = true
+	l.Unlock()
+
+	l.flushRateLimits()
+
+	l.Lock()
+	summary := l.summary()
+	emit := l.closeSummary
+	c := l.coalescer
+	l.Unlock()
+
+	if emit {
+		l.logSummary(summary)
+	}
+
+	if c != nil {
+		c.flush()
+	}
+
+	l.Lock()
+	backends := map[string]Backend{}
+	for name, backend := range l.backends {
+		backends[name] = backend
+	}
+	l.Unlock()
+
+	deadline := time.Now().Add(timeout)
+	results := make(chan drainResult, len(backends)) //Buffered so late finishers don't leak.
+
+	pending := map[string]struct{}{}
+	for name, backend := range backends {
+		pending[name] = struct{}{}
+		go func(name string, backend Backend) {
+			results <- drainResult{name: name, err: drainBackend(backend, deadline)}
+		}(name, backend)
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	var firstErr error
+	for len(pending) > 0 {
+		select {
+		case result := <-results:
+			delete(pending, result.name)
+			if result.err != nil && firstErr == nil {
+				firstErr = fmt.Errorf("Unable to drain Backend %s: %s", result.name, result.err)
+			}
+		case <-timer.C:
+			var names []string
+			for name := range pending {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return fmt.Errorf("Backends did not finish draining within %s: %s", timeout, strings.Join(names, ", "))
+		}
+	}
+
+	return firstErr
+}
+
+//drainBackend drains the specified Backend by the specified deadline if it
+//implements the Drainer interface, otherwise it is flushed if it implements
+//the Flusher interface and closed if it implements io.Closer.
+func drainBackend(backend Backend, deadline time.Time) error {
+	if d, ok := backend.(Drainer); ok {
+		return d.Drain(deadline)
+	}
+
+	if f, ok := backend.(Flusher); ok {
+		if err := f.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if c, ok := backend.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
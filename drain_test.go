@@ -0,0 +1,103 @@
+package lumberjack
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+//slowBackend is a Backend used for testing that takes the specified delay
+//to drain, recording the deadline it was given.
+type slowBackend struct {
+	delay    time.Duration
+	deadline time.Time
+	drained  chan struct{}
+}
+
+func (b *slowBackend) Log(entry *LogEntry) {}
+
+func (b *slowBackend) Drain(deadline time.Time) error {
+	b.deadline = deadline
+	time.Sleep(b.delay)
+	close(b.drained)
+	return nil
+}
+
+func TestCloseWithTimeout(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+
+	slow := &slowBackend{delay: time.Second, drained: make(chan struct{})}
+	fast := &slowBackend{drained: make(chan struct{})}
+	buffer := &bufferBackend{}
+	logger.AddBackend("slow", slow)
+	logger.AddBackend("fast", fast)
+	logger.AddBackend("buffer", buffer)
+
+	logger.Info("pending")
+
+	start := time.Now()
+	err := logger.CloseWithTimeout(50 * time.Millisecond)
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Fatal("Expected error for Backend that did not finish draining")
+	}
+	expect(t, strings.HasSuffix(err.Error(), ": slow"), true)
+	expect(t, elapsed < 500*time.Millisecond, true)
+
+	<-fast.drained
+	expect(t, fast.deadline.IsZero(), false)
+	expect(t, len(buffer.Delivered()), 1)
+	expect(t, buffer.closed, true)
+}
+
+func TestCloseWithTimeoutInTime(t *testing.T) {
+	logger := NewLogger()
+
+	fast := &slowBackend{delay: time.Millisecond, drained: make(chan struct{})}
+	logger.AddBackend("fast", fast)
+
+	if err := logger.CloseWithTimeout(time.Second); err != nil {
+		t.Fatal(err)
+	}
+}
+
+//countingCloser is a Backend used for testing that counts how many times it
+//is closed.
+type countingCloser struct {
+	closes int
+}
+
+func (b *countingCloser) Log(entry *LogEntry) {}
+
+func (b *countingCloser) Close() error {
+	b.closes++
+	return nil
+}
+
+func TestCloseWithTimeoutThenClose(t *testing.T) {
+	logger := NewLogger()
+	closer := &countingCloser{}
+	logger.AddBackend("closer", closer)
+
+	if err := logger.CloseWithTimeout(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := logger.CloseWithTimeout(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, closer.closes, 1)
+
+	//Closing first with Close is the same.
+	logger = NewLogger()
+	closer = &countingCloser{}
+	logger.AddBackend("closer", closer)
+
+	logger.Close()
+	logger.CloseWithTimeout(time.Second)
+	expect(t, closer.closes, 1)
+}
@@ -0,0 +1,100 @@
+package lumberjack
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+//ecsVersion is the version of the Elastic Common Schema produced by the
+//ECSFormatter.
+const ecsVersion = "1.6.0"
+
+//ECSFormatter implements a Formatter that renders each LogEntry as JSON
+//shaped by the Elastic Common Schema, with dotted field names such as
+//"log.origin.file.name" expanded into nested objects. The structured Fields
+//are nested under FieldsNamespace, which defaults to "labels".
+//
+//The "@timestamp" field holds the time of the LogEntry, or the time it was
+//formatted if the LogEntry has no time.
+type ECSFormatter struct {
+	FieldsNamespace string
+
+	clock Clock
+}
+
+//Format satisfies the Formatter interface's requirements used for rendering
+//a LogEntry into ECS shaped JSON.
+func (f *ECSFormatter) Format(entry *LogEntry) ([]byte, error) {
+	clock := f.clock
+	if clock == nil {
+		clock = systemClock{}
+	}
+
+	timestamp := entry.Time
+	if timestamp.IsZero() {
+		timestamp = clock.Now()
+	}
+
+	doc := map[string]interface{}{}
+	setECSField(doc, "@timestamp", timestamp.UTC().Format(time.RFC3339Nano))
+	setECSField(doc, "ecs.version", ecsVersion)
+	setECSField(doc, "message", entry.Message)
+	setECSField(doc, "log.level", entry.Level.String())
+	setECSField(doc, "log.origin.function", entry.Caller)
+	setECSField(doc, "log.origin.file.name", entry.File)
+	setECSField(doc, "log.origin.file.line", entry.Line)
+
+	optional := map[string]string{
+		"log.logger":          entry.Component,
+		"service.version":     entry.Version,
+		"service.environment": entry.Env,
+		"cloud.region":        entry.Region,
+		"host.id":             entry.InstanceID,
+		"transaction.name":    entry.Transaction,
+		"error.stack_trace":   entry.Stack,
+	}
+	for path, value := range optional {
+		if value != "" {
+			setECSField(doc, path, value)
+		}
+	}
+
+	if len(entry.Fields) > 0 || entry.Repeat > 0 {
+		namespace := f.FieldsNamespace
+		if namespace == "" {
+			namespace = "labels"
+		}
+
+		fields := map[string]interface{}{}
+		for key, value := range entry.Fields {
+			fields[key] = sanitizeValue(value)
+		}
+		if entry.Repeat > 0 {
+			fields["repeat"] = entry.Repeat
+		}
+		setECSField(doc, namespace, fields)
+	}
+
+	return json.Marshal(doc)
+}
+
+//setECSField sets the value at the specified dotted path within the
+//specified document, creating the nested objects along the path as needed.
+func setECSField(doc map[string]interface{}, path string, value interface{}) {
+	if strings.HasPrefix(path, "@") {
+		doc[path] = value
+		return
+	}
+
+	parts := strings.Split(path, ".")
+	for _, part := range parts[:len(parts)-1] {
+		child, ok := doc[part].(map[string]interface{})
+		if !ok {
+			child = map[string]interface{}{}
+			doc[part] = child
+		}
+		doc = child
+	}
+	doc[parts[len(parts)-1]] = value
+}
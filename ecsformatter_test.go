@@ -0,0 +1,88 @@
+package lumberjack
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestECSFormatter(t *testing.T) {
+	clock := newFakeClock()
+	formatter := &ECSFormatter{clock: clock}
+
+	entry := testobj.Entries[0]
+	entry.Component = "db"
+	entry.Env = "prod"
+	entry.SetField("user_id", 42)
+
+	data, err := formatter.Format(&entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := map[string]interface{}{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	expect(t, out["@timestamp"], "2015-08-17T12:23:57Z")
+	expect(t, out["message"], "Test Error")
+	expect(t, out["ecs"], map[string]interface{}{"version": ecsVersion})
+	expect(t, out["log"], map[string]interface{}{
+		"level":  "ERROR",
+		"logger": "db",
+		"origin": map[string]interface{}{
+			"function": "main.main()",
+			"file": map[string]interface{}{
+				"name": "main.go",
+				"line": float64(10),
+			},
+		},
+	})
+	expect(t, out["service"], map[string]interface{}{"environment": "prod"})
+	expect(t, out["labels"], map[string]interface{}{"user_id": float64(42)})
+
+	for _, key := range []string{"cloud", "host", "error", "level", "caller"} {
+		if _, exists := out[key]; exists {
+			t.Errorf("Expected key %q to be omitted", key)
+		}
+	}
+}
+
+func TestJSONBackendFormatter(t *testing.T) {
+	var buf bytes.Buffer
+	backend := NewJSONBackend(&buf)
+	backend.Formatter = &ECSFormatter{FieldsNamespace: "app", clock: newFakeClock()}
+
+	entry := testobj.Entries[1]
+	entry.SetField("request_id", "abc")
+	backend.Log(&entry)
+
+	out := map[string]interface{}{}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	expect(t, out["message"], "Test Info")
+	expect(t, out["app"], map[string]interface{}{"request_id": "abc"})
+}
+
+func TestMarshalBufferFormatter(t *testing.T) {
+	buffer := logbuffer{Entries: testobj.Entries, formatter: &ECSFormatter{clock: newFakeClock()}}
+
+	data, err := marshalBuffer(buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := struct {
+		Entries []map[string]interface{} `json:"logentries"`
+	}{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	expect(t, len(out.Entries), 2)
+	expect(t, out.Entries[0]["@timestamp"], "2015-08-17T12:23:57Z")
+	expect(t, out.Entries[1]["message"], "Test Info")
+}
@@ -0,0 +1,75 @@
+package lumberjack
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+//ErrorErr logs the specified message along with the specified error to all
+//added Backend objects added to the current Logger if the ERROR LogLevel is
+//currently added to the Logger. The error is added to the fields of the
+//LogEntry, with the message of every error in its chain of wrapped errors in
+//the "error.chain" field. If any error in the chain has a stack trace attached,
+//such as one created by github.com/pkg/errors, the stack trace closest to the
+//origin of the error is captured in the Stack of the LogEntry.
+//
+//Additional fields can be specified as alternating key and value arguments.
+func (l *Logger) ErrorErr(err error, message string, keysAndValues ...interface{}) {
+	skip, ok := l.begin(ERROR)
+	if !ok {
+		return
+	}
+
+	l.logWith(ERROR, skip, message, func(entry *LogEntry) {
+		for key, value := range kvFields(keysAndValues) {
+			entry.SetField(key, value)
+		}
+
+		if err == nil {
+			return
+		}
+
+		entry.SetField("error", err.Error())
+
+		var chain []string
+		for e := err; e != nil; e = errors.Unwrap(e) {
+			chain = append(chain, e.Error())
+			if stack := errorStack(e); stack != "" {
+				entry.Stack = stack
+			}
+		}
+		entry.SetField("error.chain", chain)
+	})
+}
+
+//errorStack returns the stack trace attached to the specified error if it
+//has a StackTrace method, as errors created by github.com/pkg/errors do,
+//otherwise an empty string is returned.
+func errorStack(err error) string {
+	method := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+		return ""
+	}
+	return fmt.Sprintf("%+v", method.Call(nil)[0].Interface())
+}
+
+//kvFields converts alternating key and value arguments into a map of fields.
+//Keys that are not strings are formatted as strings, and a key without a
+//value is given a placeholder value.
+func kvFields(keysAndValues []interface{}) map[string]interface{} {
+	fields := map[string]interface{}{}
+	for i := 0; i < len(keysAndValues); i += 2 {
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = fmt.Sprint(keysAndValues[i])
+		}
+
+		if i+1 < len(keysAndValues) {
+			fields[key] = keysAndValues[i+1]
+		} else {
+			fields[key] = "!MISSING_VALUE"
+		}
+	}
+	return fields
+}
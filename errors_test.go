@@ -0,0 +1,90 @@
+package lumberjack
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+//stackTrace mimics the StackTrace type of github.com/pkg/errors,
+//which formats the stack frames with %+v.
+type stackTrace []string
+
+func (s stackTrace) Format(f fmt.State, verb rune) {
+	fmt.Fprint(f, strings.Join(s, "\n"))
+}
+
+//stackError mimics an error created by github.com/pkg/errors.
+type stackError struct {
+	msg   string
+	stack stackTrace
+}
+
+func (e *stackError) Error() string          { return e.msg }
+func (e *stackError) StackTrace() stackTrace { return e.stack }
+
+func TestErrorErrWrapped(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(ERROR)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	base := errors.New("connection refused")
+	err := fmt.Errorf("query users: %w", fmt.Errorf("dial db: %w", base))
+
+	logger.ErrorErr(err, "Unable to load users", "user", 42)
+
+	entries := capture.Entries()
+	expect(t, len(entries), 1)
+
+	entry := entries[0]
+	expect(t, entry.Level, ERROR)
+	expect(t, entry.Message, "Unable to load users")
+	expect(t, entry.File, "errors_test.go")
+	expect(t, entry.Fields["user"], 42)
+	expect(t, entry.Fields["error"], "query users: dial db: connection refused")
+	expect(t, entry.Fields["error.chain"], []string{
+		"query users: dial db: connection refused",
+		"dial db: connection refused",
+		"connection refused",
+	})
+	expect(t, entry.Stack, "")
+}
+
+func TestErrorErrStackTrace(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(ERROR)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	origin := &stackError{msg: "boom", stack: stackTrace{"main.origin\n\tmain.go:10", "main.main\n\tmain.go:5"}}
+	err := fmt.Errorf("wrapped: %w", origin)
+
+	logger.ErrorErr(err, "Something failed")
+
+	entries := capture.Entries()
+	expect(t, len(entries), 1)
+	expect(t, entries[0].Fields["error.chain"], []string{"wrapped: boom", "boom"})
+	expect(t, entries[0].Stack, "main.origin\n\tmain.go:10\nmain.main\n\tmain.go:5")
+}
+
+func TestErrorErrDisabled(t *testing.T) {
+	logger := NewLogger()
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	logger.ErrorErr(errors.New("boom"), "Something failed")
+	expect(t, len(capture.Entries()), 0)
+}
+
+func TestKVFields(t *testing.T) {
+	expect(t, kvFields([]interface{}{"a", 1, 2, "b", "c"}), map[string]interface{}{
+		"a": 1,
+		"2": "b",
+		"c": "!MISSING_VALUE",
+	})
+}
@@ -0,0 +1,115 @@
+package lumberjack
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+//maxBackoffShift caps the exponential backoff of a failed endpoint at
+//2^maxBackoffShift times the base backoff.
+const maxBackoffShift = 6
+
+//failover routes the requests of a network backend to the first healthy of
+//several endpoints, in order of preference. An endpoint that fails is demoted
+//and skipped until its backoff has passed, doubling with each consecutive
+//failure, so that the backend recovers to the preferred endpoint once it is
+//healthy again.
+type failover struct {
+	endpoints []endpoint
+	backoff   time.Duration
+	clock     Clock
+	sync.Mutex
+}
+
+//endpoint holds the health of a single endpoint used by a failover.
+type endpoint struct {
+	url      string
+	failures int
+	retryAt  time.Time
+}
+
+//newFailover returns a failover over the specified endpoint urls, in order
+//of preference, that backs off of failed endpoints for the specified base
+//backoff time.Duration.
+func newFailover(backoff time.Duration, urls []string) *failover {
+	f := &failover{backoff: backoff, clock: systemClock{}}
+	for _, url := range urls {
+		f.endpoints = append(f.endpoints, endpoint{url: url})
+	}
+	return f
+}
+
+//setPrimary adds the specified url as the most preferred endpoint.
+func (f *failover) setPrimary(url string) {
+	f.Lock()
+	defer f.Unlock()
+	f.endpoints = append([]endpoint{{url: url}}, f.endpoints...)
+}
+
+//replacePrimary replaces the primary url with the specified url, which starts
+//out healthy.
+func (f *failover) replacePrimary(url string) {
+	f.Lock()
+	defer f.Unlock()
+	f.endpoints[0] = endpoint{url: url}
+}
+
+//send sends the specified logbuffer to the first healthy endpoint, falling
+//over to the next on failure. If every endpoint is backing off, the one due
+//to be retried soonest is tried rather than dropping the logbuffer.
+func (f *failover) send(buffer logbuffer) error {
+	f.Lock()
+	defer f.Unlock()
+
+	if len(f.endpoints) == 0 {
+		return fmt.Errorf("HTTP Backend: no endpoints to send to")
+	}
+
+	now := f.clock.Now()
+	tried := false
+	var lastErr error
+
+	for i := range f.endpoints {
+		if f.endpoints[i].failures > 0 && now.Before(f.endpoints[i].retryAt) {
+			continue //Still backing off, demoted until it's due.
+		}
+
+		tried = true
+		if lastErr = f.try(i, buffer, now); lastErr == nil {
+			return nil
+		}
+	}
+
+	if tried {
+		return lastErr
+	}
+
+	soonest := 0
+	for i := range f.endpoints {
+		if f.endpoints[i].retryAt.Before(f.endpoints[soonest].retryAt) {
+			soonest = i
+		}
+	}
+	return f.try(soonest, buffer, now)
+}
+
+//try sends the specified logbuffer to the endpoint at the specified index and
+//updates its health. The caller must hold the lock.
+func (f *failover) try(i int, buffer logbuffer, now time.Time) error {
+	e := &f.endpoints[i]
+
+	err := doSend(e.url, buffer)
+	if err == nil {
+		e.failures = 0
+		return nil
+	}
+
+	shift := e.failures
+	if shift > maxBackoffShift {
+		shift = maxBackoffShift
+	}
+	e.failures++
+	e.retryAt = now.Add(f.backoff << uint(shift))
+	return err
+}
@@ -0,0 +1,108 @@
+package lumberjack
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHttpBackendFailover(t *testing.T) {
+	var failing int32 = 1
+	var primaryCount int32
+
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&failing) == 1 {
+			w.WriteHeader(500)
+			return
+		}
+
+		b := logbuffer{}
+		if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+			t.Error(err)
+		}
+		atomic.AddInt32(&primaryCount, int32(len(b.Entries)))
+		w.WriteHeader(200)
+	}))
+	defer primary.Close()
+
+	secondary, secondaryCount := countingServer(t)
+	defer secondary.Close()
+
+	h := NewHttpClientBackendSync(primary.URL, Failover(time.Minute, secondary.URL))
+	defer close(h.Stop)
+
+	clock := newFakeClock()
+	h.failover.Lock()
+	h.failover.clock = clock
+	h.failover.Unlock()
+
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+	logger.AddBackend("http", h)
+
+	//The primary fails, so the entry falls over to the secondary.
+	logger.Info("one")
+	expect(t, secondaryCount(), 1)
+
+	//The primary is demoted while backing off, even after it recovers.
+	atomic.StoreInt32(&failing, 0)
+	logger.Info("two")
+	expect(t, secondaryCount(), 2)
+	expect(t, atomic.LoadInt32(&primaryCount), int32(0))
+
+	//Once the backoff passes, the primary is preferred again.
+	clock.Advance(time.Minute)
+	logger.Info("three")
+	logger.Info("four")
+	expect(t, atomic.LoadInt32(&primaryCount), int32(2))
+	expect(t, secondaryCount(), 2)
+}
+
+func TestFailoverBackoff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(500)
+	}))
+	defer server.Close()
+
+	f := newFailover(time.Second, []string{server.URL})
+	clock := newFakeClock()
+	f.clock = clock
+
+	//With every endpoint backing off, the soonest is still tried.
+	for i := 0; i < 3; i++ {
+		if err := f.send(logbuffer{Entries: []LogEntry{{Message: "fail"}}}); err == nil {
+			t.Fatal("Expected error sending to failing endpoint")
+		}
+	}
+
+	expect(t, f.endpoints[0].failures, 3)
+	expect(t, f.endpoints[0].retryAt, clock.Now().Add(4*time.Second))
+}
+
+func TestHttpBackendSetURLFailover(t *testing.T) {
+	primary, primaryCount := countingServer(t)
+	defer primary.Close()
+	replacement, replacementCount := countingServer(t)
+	defer replacement.Close()
+	secondary, secondaryCount := countingServer(t)
+	defer secondary.Close()
+
+	h := NewHttpClientBackendSync(primary.URL, Failover(time.Minute, secondary.URL))
+	defer close(h.Stop)
+
+	if err := h.SetURL(replacement.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	entry := testobj.Entries[0]
+	h.Log(&entry)
+
+	//The replacement takes the place of the primary, ahead of the fallback.
+	expect(t, len(h.failover.endpoints), 2)
+	expect(t, primaryCount(), 0)
+	expect(t, replacementCount(), 1)
+	expect(t, secondaryCount(), 0)
+}
@@ -0,0 +1,111 @@
+package lumberjack
+
+import (
+	"io"
+	"os"
+	"sync"
+)
+
+//fallbackBackendName is the name the stderr fallback Backend is added with.
+const fallbackBackendName = reservedBackendPrefix + "stderr"
+
+//fallbackWriter is the io.Writer the stderr fallback Backend prints to, which
+//is replaced in tests.
+var fallbackWriter io.Writer = os.Stderr
+
+//AddBackendWithFallback calls the specified function to initialize a Backend,
+//such as one opening a log file with NewFileBackend, and adds it to the current
+//Logger with the specified name. If initialization fails, an internal warning
+//is logged and a PrintBackend printing to os.Stderr is added in its place, so
+//that logging never silently goes dark, and the error is returned. The stderr
+//fallback is only added once however many backends fail.
+//
+//If the Backend implements the ErrorLogger interface, such as the FileBackend,
+//its first write is checked too, and if it fails, the stderr fallback is added
+//alongside it and receives that LogEntry. Only the first write is checked, any
+//later failure is logged internally by the Backend as usual.
+func (l *Logger) AddBackendWithFallback(name string, open func() (Backend, error)) error {
+	if err := validBackendName(name); err != nil {
+		return err
+	}
+
+	backend, err := open()
+	if err == nil {
+		if _, ok := backend.(ErrorLogger); ok {
+			return l.AddBackend(name, &firstWriteCheck{backend: backend, name: name, logger: l})
+		}
+		return l.AddBackend(name, backend)
+	}
+
+	logInternalf(WARN, "Backend %s failed to initialize, falling back to stderr: %s", name, err)
+	l.addFallback()
+	return err
+}
+
+//addFallback adds the stderr fallback Backend to the current Logger, unless
+//it is already added, and returns it.
+func (l *Logger) addFallback() Backend {
+	l.addBackend(fallbackBackendName, &PrintBackend{Verbosity: ERROR, Writer: fallbackWriter}) //Already added if another failed first.
+
+	l.Lock()
+	defer l.Unlock()
+	return l.backends[fallbackBackendName]
+}
+
+//firstWriteCheck wraps a Backend implementing the ErrorLogger interface added
+//with AddBackendWithFallback until its first LogEntry is written, adding the
+//stderr fallback if that fails. Once checked, the Backend replaces it.
+type firstWriteCheck struct {
+	backend Backend
+	name    string
+	logger  *Logger
+	once    sync.Once
+}
+
+//Log satisfies the Backend interfaces requirements used for accepting
+//LogEntry objects, checking the first is written by the wrapped Backend.
+func (c *firstWriteCheck) Log(entry *LogEntry) {
+	checked := false
+	c.once.Do(func() {
+		checked = true
+		err := c.backend.(ErrorLogger).LogErr(entry)
+		c.unwrap()
+
+		if err != nil {
+			logInternalf(WARN, "Backend %s failed its first write, falling back to stderr: %s", c.name, err)
+			if fallback := c.logger.addFallback(); fallback != nil {
+				fallback.Log(entry)
+			}
+		}
+	})
+
+	if !checked {
+		c.backend.Log(entry)
+	}
+}
+
+//unwrap replaces the firstWriteCheck with the Backend it wraps, unless the
+//Backend was removed or replaced in the meantime.
+func (c *firstWriteCheck) unwrap() {
+	c.logger.Lock()
+	defer c.logger.Unlock()
+	if c.logger.backends[c.name] == Backend(c) {
+		c.logger.backends[c.name] = c.backend
+	}
+}
+
+//Flush flushes the wrapped Backend if it implements the Flusher interface.
+func (c *firstWriteCheck) Flush() error {
+	if f, ok := c.backend.(Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+//Close closes the wrapped Backend if it implements io.Closer.
+func (c *firstWriteCheck) Close() error {
+	if closer, ok := c.backend.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package lumberjack
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAddBackendWithFallback(t *testing.T) {
+	var buf bytes.Buffer
+
+	path, cleanup := tempLogPath(t)
+	defer cleanup()
+
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+	defer logger.Close()
+
+	openFile := func(path string) func() (Backend, error) {
+		return func() (Backend, error) {
+			return NewFileBackend(path)
+		}
+	}
+
+	// The directory doesn't exist, so the file can't be opened.
+	missing := filepath.Join(path, "missing", "app.log")
+	if err := logger.AddBackendWithFallback("file", openFile(missing)); err == nil {
+		t.Fatal("Expected an error opening a file in a missing directory")
+	}
+	if err := logger.AddBackendWithFallback("other", openFile(missing)); err == nil {
+		t.Fatal("Expected an error opening a file in a missing directory")
+	}
+
+	logger.Lock()
+	expect(t, len(logger.backends), 1)
+	fallback, isPrint := logger.backends[fallbackBackendName].(*PrintBackend)
+	logger.Unlock()
+	expect(t, isPrint, true)
+	expect(t, fallback.Writer, os.Stderr)
+
+	fallback.Writer = &buf
+	logger.Info("still logging")
+	expect(t, strings.Contains(buf.String(), "still logging"), true)
+
+	// A backend that opens is added as usual.
+	if err := logger.AddBackendWithFallback("file", openFile(path)); err != nil {
+		t.Fatal(err)
+	}
+	logger.Info("to the file")
+	expect(t, len(readLines(t, path)), 1)
+}
+
+func TestAddBackendWithFallbackReplaysBootstrap(t *testing.T) {
+	var buf bytes.Buffer
+	fallbackWriter = &buf
+	defer func() { fallbackWriter = os.Stderr }()
+
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+	logger.EnableBootstrapBuffer(10)
+
+	logger.Info("before any backend")
+
+	failing := func() (Backend, error) {
+		return nil, errors.New("unwritable")
+	}
+	if err := logger.AddBackendWithFallback("file", failing); err == nil {
+		t.Fatal("Expected the initialization error")
+	}
+
+	expect(t, strings.Contains(buf.String(), "before any backend"), true)
+}
+
+func TestAddBackendWithFallbackFirstWrite(t *testing.T) {
+	var buf bytes.Buffer
+	fallbackWriter = &buf
+	defer func() { fallbackWriter = os.Stderr }()
+
+	path, cleanup := tempLogPath(t)
+	defer cleanup()
+
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+
+	// The file opens, but is closed underneath the backend so writing fails.
+	var file *FileBackend
+	open := func() (Backend, error) {
+		var err error
+		file, err = NewFileBackend(path)
+		if err == nil {
+			file.file.Close()
+		}
+		return file, err
+	}
+	if err := logger.AddBackendWithFallback("file", open); err != nil {
+		t.Fatal(err)
+	}
+
+	// Until the first write, the backend is reachable as itself.
+	backend, err := logger.GetBackend("file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, backend, Backend(file))
+
+	logger.Info("first write")
+	expect(t, strings.Contains(buf.String(), "first write"), true)
+
+	logger.Lock()
+	expect(t, logger.backends["file"], Backend(file))
+	_, fallback := logger.backends[fallbackBackendName]
+	logger.Unlock()
+	expect(t, fallback, true)
+}
+
+func TestAddBackendWithFallbackFirstWriteSucceeds(t *testing.T) {
+	path, cleanup := tempLogPath(t)
+	defer cleanup()
+
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+	defer logger.Close()
+
+	open := func() (Backend, error) {
+		return NewFileBackend(path)
+	}
+	if err := logger.AddBackendWithFallback("file", open); err != nil {
+		t.Fatal(err)
+	}
+
+	logger.Info("first write")
+	logger.Info("second write")
+	expect(t, len(readLines(t, path)), 2)
+
+	logger.Lock()
+	_, isFile := logger.backends["file"].(*FileBackend)
+	_, fallback := logger.backends[fallbackBackendName]
+	logger.Unlock()
+	expect(t, isFile, true)
+	expect(t, fallback, false)
+}
@@ -0,0 +1,71 @@
+package lumberjack
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+//FieldNamer is a function used by the backends that emit JSON to rename the
+//top level keys of each LogEntry, such as "level" and "message", to match
+//the conventions expected by a downstream system. The structured Fields are
+//left as they are.
+type FieldNamer func(key string) string
+
+//ECSFieldNames is a FieldNamer that renames the keys of each LogEntry to
+//their Elastic Common Schema equivalents.
+var ECSFieldNames = RenameFieldNames(map[string]string{
+	"level":       "log.level",
+	"caller":      "log.origin.function",
+	"file":        "log.origin.file.name",
+	"line":        "log.origin.file.line",
+	"version":     "service.version",
+	"env":         "service.environment",
+	"region":      "cloud.region",
+	"instance_id": "host.id",
+	"stack":       "error.stack_trace",
+	"transaction": "transaction.name",
+})
+
+//CamelCaseFieldNames is a FieldNamer that renames snake_case keys to
+//camelCase, such as "instance_id" to "instanceId".
+func CamelCaseFieldNames(key string) string {
+	parts := strings.Split(key, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+//RenameFieldNames returns a FieldNamer that renames the keys found in the
+//specified mapping, leaving any other keys as they are.
+func RenameFieldNames(mapping map[string]string) FieldNamer {
+	return func(key string) string {
+		if renamed, ok := mapping[key]; ok {
+			return renamed
+		}
+		return key
+	}
+}
+
+//marshalEntryNamed Marshals a LogEntry into JSON like marshalEntry, then
+//renames the top level keys with the specified FieldNamer, if any.
+func marshalEntryNamed(entry *LogEntry, compact bool, namer FieldNamer) ([]byte, error) {
+	data, err := marshalEntry(entry, compact)
+	if err != nil || namer == nil {
+		return data, err
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	renamed := make(map[string]json.RawMessage, len(fields))
+	for key, value := range fields {
+		renamed[namer(key)] = value
+	}
+
+	return json.Marshal(renamed)
+}
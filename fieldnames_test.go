@@ -0,0 +1,80 @@
+package lumberjack
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestCamelCaseFieldNames(t *testing.T) {
+	expect(t, CamelCaseFieldNames("instance_id"), "instanceId")
+	expect(t, CamelCaseFieldNames("level"), "level")
+	expect(t, CamelCaseFieldNames("a_b_c"), "aBC")
+}
+
+func TestJSONBackendRenamedFields(t *testing.T) {
+	var buf bytes.Buffer
+	backend := NewJSONBackend(&buf)
+	backend.FieldNames = RenameFieldNames(map[string]string{"level": "severity", "message": "msg"})
+
+	entry := testobj.Entries[0]
+	entry.InstanceID = "i-123"
+	entry.SetField("user_id", 42)
+	backend.Log(&entry)
+
+	out := map[string]interface{}{}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	expect(t, out["severity"], "ERROR")
+	expect(t, out["msg"], "Test Error")
+	expect(t, out["instance_id"], "i-123")
+	expect(t, out["fields"], map[string]interface{}{"user_id": float64(42)})
+
+	for _, key := range []string{"level", "message"} {
+		if _, exists := out[key]; exists {
+			t.Errorf("Expected key %q to be renamed", key)
+		}
+	}
+}
+
+func TestJSONBackendCamelCaseFields(t *testing.T) {
+	var buf bytes.Buffer
+	backend := NewJSONBackend(&buf)
+	backend.FieldNames = CamelCaseFieldNames
+
+	entry := testobj.Entries[0]
+	entry.InstanceID = "i-123"
+	backend.Log(&entry)
+
+	out := map[string]interface{}{}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	expect(t, out["instanceId"], "i-123")
+	expect(t, out["level"], "ERROR")
+}
+
+func TestMarshalBufferECSFieldNames(t *testing.T) {
+	buffer := logbuffer{Entries: testobj.Entries, namer: ECSFieldNames}
+
+	data, err := marshalBuffer(buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := struct {
+		Entries []map[string]interface{} `json:"logentries"`
+	}{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	expect(t, len(out.Entries), 2)
+	expect(t, out.Entries[0]["log.level"], "ERROR")
+	expect(t, out.Entries[0]["log.origin.function"], "main.main()")
+	expect(t, out.Entries[0]["log.origin.file.line"], float64(10))
+	expect(t, out.Entries[1]["message"], "Test Info")
+}
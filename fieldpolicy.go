@@ -0,0 +1,74 @@
+package lumberjack
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+//FieldPolicy is used to choose how a Logger handles structured Fields that
+//can't be Marshalled into JSON, such as channels, functions, or cyclic
+//structures.
+type FieldPolicy byte
+
+//Constants used to define the FieldPolicies.
+const (
+	//FieldsUnchecked leaves the Fields as they are, relying on each
+	//backend to handle any that fail to Marshal. This is the default.
+	FieldsUnchecked FieldPolicy = iota
+
+	//FieldsCoerce replaces any value that fails to Marshal with its
+	//fmt "%v" string, and lists the replaced keys in the "sanitized" field.
+	FieldsCoerce
+
+	//FieldsReject removes any value that fails to Marshal and logs an
+	//error internally describing it.
+	FieldsReject
+)
+
+//sanitizedField is the key of the field listing the keys of the Fields
+//coerced to strings by the FieldsCoerce FieldPolicy.
+const sanitizedField = "sanitized"
+
+//SetFieldPolicy sets the FieldPolicy the current Logger applies to the
+//structured Fields of every LogEntry before it is sent to the backends, so
+//that every LogEntry is guaranteed to be safe to Marshal into JSON.
+func (l *Logger) SetFieldPolicy(policy FieldPolicy) error {
+	if policy > FieldsReject {
+		return fmt.Errorf("Invalid FieldPolicy: %d", policy)
+	}
+	l.Lock()
+	defer l.Unlock()
+	l.fieldPolicy = policy
+	return nil
+}
+
+//applyFieldPolicy applies the specified FieldPolicy to the Fields of the
+//specified LogEntry.
+func applyFieldPolicy(policy FieldPolicy, entry *LogEntry) {
+	if policy == FieldsUnchecked || len(entry.Fields) == 0 {
+		return
+	}
+
+	var sanitized []string
+	for key, value := range entry.Fields {
+		_, err := json.Marshal(value)
+		if err == nil {
+			continue
+		}
+
+		switch policy {
+		case FieldsCoerce:
+			entry.Fields[key] = fmt.Sprintf("%v", value)
+			sanitized = append(sanitized, key)
+		case FieldsReject:
+			delete(entry.Fields, key)
+			logInternalf(ERROR, "Removed field %q that can't be Marshalled into JSON: %s", key, err)
+		}
+	}
+
+	if len(sanitized) > 0 {
+		sort.Strings(sanitized)
+		entry.Fields[sanitizedField] = sanitized
+	}
+}
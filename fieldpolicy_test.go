@@ -0,0 +1,72 @@
+package lumberjack
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"os"
+	"strings"
+	"testing"
+)
+
+//cyclic is a structure that refers back to itself and fails to Marshal.
+type cyclic struct {
+	Name string
+	Next *cyclic
+}
+
+func TestFieldPolicyCoerce(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(ERROR)
+
+	var buf bytes.Buffer
+	logger.AddBackend("json", NewJSONBackend(&buf))
+
+	if err := logger.SetFieldPolicy(FieldsCoerce); err != nil {
+		t.Fatal(err)
+	}
+
+	loop := &cyclic{Name: "loop"}
+	loop.Next = loop
+
+	logger.ErrorErr(nil, "bad fields", "ch", make(chan int), "fn", func() {}, "loop", loop, "ok", 1)
+
+	out := map[string]interface{}{}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	fields := out["fields"].(map[string]interface{})
+	expect(t, fields["ok"], float64(1))
+	expect(t, fields["sanitized"], []interface{}{"ch", "fn", "loop"})
+	for _, key := range []string{"ch", "fn", "loop"} {
+		if _, ok := fields[key].(string); !ok {
+			t.Errorf("Expected field %q to be coerced to a string, got %v", key, fields[key])
+		}
+	}
+	expect(t, strings.HasPrefix(fields["loop"].(string), "&{loop"), true)
+}
+
+func TestFieldPolicyReject(t *testing.T) {
+	var internal bytes.Buffer
+	log.SetOutput(&internal)
+	defer log.SetOutput(os.Stderr)
+
+	logger := NewLogger()
+	logger.AddLevel(ERROR)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+	logger.SetFieldPolicy(FieldsReject)
+
+	logger.ErrorErr(nil, "bad fields", "ch", make(chan int), "ok", "yes")
+
+	entries := capture.Entries()
+	expect(t, len(entries), 1)
+	expect(t, entries[0].Fields, map[string]interface{}{"ok": "yes"})
+	expect(t, strings.Contains(internal.String(), `Removed field "ch"`), true)
+
+	if err := logger.SetFieldPolicy(FieldPolicy(9)); err == nil {
+		t.Error("Expected error for invalid FieldPolicy")
+	}
+}
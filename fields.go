@@ -0,0 +1,28 @@
+package lumberjack
+
+//WithField returns a child Logger that shares the configuration of the
+//current Logger and sets the specified structured field on every LogEntry it
+//sends, along with any fields of the current Logger. Fields set on a single
+//LogEntry, such as with ErrorErr, take precedence over the fields of the
+//Logger.
+func (l *Logger) WithField(key string, value interface{}) *Logger {
+	return l.WithFields(map[string]interface{}{key: value})
+}
+
+//WithFields returns a child Logger that shares the configuration of the
+//current Logger and sets the specified structured fields on every LogEntry it
+//sends, along with any fields of the current Logger. Where a key is already
+//set on the current Logger, the specified value replaces it.
+func (l *Logger) WithFields(fields map[string]interface{}) *Logger {
+	merged := make(map[string]interface{}, len(l.fields)+len(fields))
+	for key, value := range l.fields {
+		merged[key] = value
+	}
+	for key, value := range fields {
+		merged[key] = value
+	}
+
+	child := *l
+	child.fields = merged
+	return &child
+}
@@ -0,0 +1,58 @@
+package lumberjack
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWithField(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+	logger.AddLevel(ERROR)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	request := logger.WithField("request_id", "abc")
+	user := request.WithField("user", 42)
+
+	user.Info("chained")
+	request.Info("parent")
+	logger.Info("root")
+	user.ErrorErr(nil, "override", "user", 7)
+
+	entries := capture.Entries()
+	expect(t, len(entries), 4)
+	expect(t, entries[0].Fields, map[string]interface{}{"request_id": "abc", "user": 42})
+	expect(t, entries[1].Fields, map[string]interface{}{"request_id": "abc"})
+	expect(t, len(entries[2].Fields), 0)
+	expect(t, entries[3].Fields, map[string]interface{}{"request_id": "abc", "user": 7})
+}
+
+func TestWithFields(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	fields := map[string]interface{}{"a": 1, "b": 2}
+	child := logger.WithFields(fields).WithFields(map[string]interface{}{"b": 3})
+	fields["a"] = 100
+
+	child.Info("merged")
+
+	entries := capture.Entries()
+	expect(t, len(entries), 1)
+	expect(t, entries[0].Fields, map[string]interface{}{"a": 1, "b": 3})
+}
+
+func TestPrintBackendFields(t *testing.T) {
+	var buf bytes.Buffer
+
+	entry := testobj.Entries[1]
+	entry.Fields = map[string]interface{}{"user": 42, "path": "/a b"}
+	(&PrintBackend{Verbosity: ERROR, Writer: &buf}).Log(&entry)
+
+	expect(t, buf.String(), "(INFO) @ main.main()(): Test Info path=\"/a b\" user=42\n")
+}
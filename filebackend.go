@@ -0,0 +1,443 @@
+package lumberjack
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+//FileBackend implements a Backend that writes each LogEntry to a log file
+//as a formatted line, using the same formats as the PrintBackend based on
+//the Verbosity specified, with the LogLevel of each line rendered in the
+//LevelCase and LevelStyle specified. If Formatter is set, each LogEntry is
+//rendered by it instead, such as with the Syslog5424Formatter.
+//
+//Writes may optionally be buffered, in which case the buffered lines are
+//written to the file when the line count threshold is reached, when the
+//oldest buffered line reaches the max age, or when Flush is called.
+//
+//If AtomicAppend is true, buffering is bypassed and each line is written to
+//the log file with a single write call, so that several processes appending
+//to the same log file don't interleave their lines. The operating system only
+//guarantees this for lines of up to AtomicWriteSize bytes, longer lines may be
+//interleaved with the lines of other processes.
+//
+//If MaxSizeBytes is set, the log file is rotated as with Rotate once a write
+//takes it past that size. If MaxBackups is set, the oldest rotated log files
+//beyond that count are deleted whenever the log file is rotated.
+type FileBackend struct {
+	Verbosity    LogLevel
+	LevelCase    LevelCase
+	LevelStyle   LevelStyle
+	Formatter    Formatter
+	AtomicAppend bool
+	MaxSizeBytes int64
+	MaxBackups   int
+
+	path     string
+	file     *os.File
+	size     int64
+	writer   *bufio.Writer
+	maxLines int
+	maxAge   time.Duration
+	lines    int
+	oldest   time.Time
+	clock    Clock
+	stop     chan struct{}
+	closed   bool
+	sync.Mutex
+}
+
+//NewFileBackend opens the file at the specified path for appending,
+//creating it if it does not exist, and returns an instance of FileBackend
+//that writes each LogEntry to the file as it is logged. An error is returned
+//if the file can't be opened.
+func NewFileBackend(path string) (*FileBackend, error) {
+	return NewBufferedFileBackend(path, 0, 0)
+}
+
+//NewBufferedFileBackend opens the file at the specified path for appending,
+//creating it if it does not exist, and returns an instance of FileBackend
+//that buffers writes to the file. An error is returned if the file can't be
+//opened.
+//
+//The buffer is written to the file once it holds maxLines lines, or once the
+//oldest line in the buffer is older than maxAge, whichever occurs first. If
+//neither is specified, each LogEntry is written to the file individually.
+func NewBufferedFileBackend(path string, maxLines int, maxAge time.Duration) (*FileBackend, error) {
+	file, err := openLogFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &FileBackend{
+		Verbosity: ERROR,
+		path:      path,
+		file:      file,
+		size:      fileSize(file),
+		writer:    bufio.NewWriter(file),
+		maxLines:  maxLines,
+		maxAge:    maxAge,
+		clock:     systemClock{},
+		stop:      make(chan struct{}),
+	}
+
+	if maxAge > 0 {
+		go b.startFlusher()
+	}
+
+	return b, nil
+}
+
+//Log satisfies the Backend interfaces requirements used for accepting
+//LogEntry objects to write out to the log file.
+func (b *FileBackend) Log(entry *LogEntry) {
+	if err := b.LogErr(entry); err != nil {
+		logInternal(ERROR, err)
+	}
+}
+
+//LogErr satisfies the ErrorLogger interfaces requirements, writing out the
+//specified LogEntry to the log file as Log does, and returning the error
+//writing it, if any. While writes are buffered, an error writing the buffer
+//to the log file is returned for the LogEntry that caused it to be written.
+func (b *FileBackend) LogErr(entry *LogEntry) error {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.closed {
+		return nil
+	}
+
+	now := b.clock.Now()
+	var line string
+	if b.Formatter != nil {
+		data, err := b.Formatter.Format(entry)
+		if err != nil {
+			return fmt.Errorf("File Backend: unable to format LogEntry: %s", err)
+		}
+		line = string(data) + "\n"
+	} else {
+		line = now.Format("2006/01/02 15:04:05 ") + formatEntry(b.Verbosity, entry.Level.format(b.LevelCase, b.LevelStyle), entry) + "\n"
+	}
+
+	if b.AtomicAppend {
+		err := b.writeAtomic(line)
+		if _, rotateErr := b.rotateIfFull(); err == nil {
+			err = rotateErr
+		}
+		return err
+	}
+
+	if _, err := b.writer.WriteString(line); err != nil {
+		return fmt.Errorf("File Backend: unable to write LogEntry: %s", err)
+	}
+	b.size += int64(len(line))
+
+	if rotated, err := b.rotateIfFull(); rotated {
+		return err
+	}
+
+	if b.lines == 0 {
+		b.oldest = now
+	}
+	b.lines++
+
+	if b.maxLines > 0 && b.lines < b.maxLines {
+		return nil //Still room in the buffer, wait for it to fill or go stale.
+	}
+
+	if b.maxLines <= 0 && b.maxAge > 0 && now.Sub(b.oldest) < b.maxAge {
+		return nil //Only buffering by age, wait for it to go stale.
+	}
+
+	return b.flush()
+}
+
+//AtomicWriteSize is the largest line in bytes that a FileBackend with
+//AtomicAppend set is guaranteed to write without interleaving with other
+//processes, being the smallest PIPE_BUF allowed by POSIX.
+const AtomicWriteSize = 512
+
+//writeAtomic is an internal method that writes any buffered lines to the log
+//file, then writes the specified line with a single write call, returning the
+//first error encountered, if any. The caller must hold the lock.
+func (b *FileBackend) writeAtomic(line string) error {
+	flushErr := b.flush()
+
+	n, err := b.file.Write([]byte(line))
+	b.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("File Backend: unable to write LogEntry: %s", err)
+	}
+	return flushErr
+}
+
+//rotateIfFull is an internal method that rotates the log file if it has grown
+//past MaxSizeBytes, and reports whether it was rotated along with the error
+//rotating it, if any. The caller must hold the lock.
+func (b *FileBackend) rotateIfFull() (bool, error) {
+	if b.MaxSizeBytes <= 0 || b.size <= b.MaxSizeBytes {
+		return false, nil
+	}
+	return true, b.rotate()
+}
+
+//Flush writes any buffered lines to the log file.
+func (b *FileBackend) Flush() error {
+	b.Lock()
+	defer b.Unlock()
+	return b.flush()
+}
+
+//Close stops buffering, flushes any buffered lines, and closes the log file.
+//Calling Close more than once has no effect.
+func (b *FileBackend) Close() error {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.closed {
+		return nil
+	}
+	b.closed = true
+	close(b.stop)
+
+	flushErr := b.flush()
+	if err := b.file.Close(); err != nil {
+		return fmt.Errorf("File Backend: unable to close log file: %s", err)
+	}
+	return flushErr
+}
+
+//Rotate flushes any buffered lines and closes the current log file, renames it
+//with a timestamp suffix, then opens a fresh log file at the original path that
+//all subsequent lines are written to. It is safe to call while other Goroutines
+//are logging to the FileBackend.
+func (b *FileBackend) Rotate() error {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.closed {
+		return fmt.Errorf("File Backend: unable to rotate closed log file")
+	}
+
+	return b.rotate()
+}
+
+//rotate is an internal method that closes the current log file, renames it
+//with a timestamp suffix, then opens a fresh log file at the original path.
+//The caller must hold the lock.
+func (b *FileBackend) rotate() error {
+	if err := b.flush(); err != nil {
+		return err
+	}
+
+	if err := b.file.Close(); err != nil {
+		return fmt.Errorf("File Backend: unable to close log file: %s", err)
+	}
+
+	backup := b.path + "." + b.clock.Now().Format(backupTimeFormat)
+	for i := 1; fileExists(backup); i++ {
+		backup = fmt.Sprintf("%s.%s.%d", b.path, b.clock.Now().Format(backupTimeFormat), i)
+	}
+
+	renameErr := os.Rename(b.path, backup)
+
+	//Always reopen, so that logging continues even if the rename failed.
+	file, err := openLogFile(b.path)
+	if err != nil {
+		b.closed = true
+		close(b.stop)
+		return err
+	}
+
+	b.file = file
+	b.size = fileSize(file)
+	b.writer.Reset(file)
+
+	if renameErr != nil {
+		return fmt.Errorf("File Backend: unable to rename log file: %s", renameErr)
+	}
+	return b.removeOldBackups()
+}
+
+//removeOldBackups is an internal method that deletes the oldest rotated log
+//files beyond MaxBackups, if it is set. The caller must hold the lock.
+func (b *FileBackend) removeOldBackups() error {
+	if b.MaxBackups <= 0 {
+		return nil
+	}
+
+	backups, err := b.backups()
+	if err != nil {
+		return err
+	}
+
+	for len(backups) > b.MaxBackups {
+		if err := os.Remove(backups[0]); err != nil {
+			return fmt.Errorf("File Backend: unable to remove old log file: %s", err)
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
+
+//backups is an internal method that returns the paths of the rotated log
+//files, oldest first. The directory is listed rather than globbed, so that a
+//path holding characters such as [, *, or ? is matched literally.
+func (b *FileBackend) backups() ([]string, error) {
+	dir, err := os.Open(filepath.Dir(b.path))
+	if err != nil {
+		return nil, fmt.Errorf("File Backend: unable to list old log files: %s", err)
+	}
+	names, err := dir.Readdirnames(-1)
+	dir.Close()
+	if err != nil {
+		return nil, fmt.Errorf("File Backend: unable to list old log files: %s", err)
+	}
+
+	prefix := filepath.Base(b.path) + "."
+
+	var backups []backupFile
+	for _, name := range names {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if backup, ok := parseBackup(strings.TrimPrefix(name, prefix)); ok {
+			backup.path = filepath.Join(filepath.Dir(b.path), name)
+			backups = append(backups, backup)
+		}
+	}
+
+	//Files rotated at the same time sort by their counter suffix, after the
+	//first one which has none.
+	sort.Slice(backups, func(i, j int) bool {
+		if !backups[i].rotated.Equal(backups[j].rotated) {
+			return backups[i].rotated.Before(backups[j].rotated)
+		}
+		return backups[i].counter < backups[j].counter
+	})
+
+	paths := make([]string, len(backups))
+	for i, backup := range backups {
+		paths[i] = backup.path
+	}
+	return paths, nil
+}
+
+//backupFile holds the path of a rotated log file along with the time it was
+//rotated and the counter suffix added if another was rotated at that time.
+type backupFile struct {
+	path    string
+	rotated time.Time
+	counter int
+}
+
+//parseBackup is an internal function that parses the suffix of a rotated log
+//file, being the timestamp it was rotated at followed by an optional counter,
+//returning false if the suffix is not that of a rotated log file.
+func parseBackup(suffix string) (backupFile, bool) {
+	if len(suffix) < len(backupTimeFormat) {
+		return backupFile{}, false
+	}
+
+	rotated, err := time.Parse(backupTimeFormat, suffix[:len(backupTimeFormat)])
+	if err != nil {
+		return backupFile{}, false
+	}
+
+	backup := backupFile{rotated: rotated}
+	if rest := suffix[len(backupTimeFormat):]; rest != "" {
+		if !strings.HasPrefix(rest, ".") {
+			return backupFile{}, false
+		}
+		counter, err := strconv.Atoi(rest[1:])
+		if err != nil || counter < 1 {
+			return backupFile{}, false
+		}
+		backup.counter = counter
+	}
+	return backup, true
+}
+
+//flush is an internal method that writes any buffered lines to the log
+//file. The caller must hold the lock.
+func (b *FileBackend) flush() error {
+	b.lines = 0
+	if err := b.writer.Flush(); err != nil {
+		return fmt.Errorf("File Backend: unable to flush log file: %s", err)
+	}
+	return nil
+}
+
+//flushStale is an internal method that writes the buffered lines to the log
+//file if the oldest buffered line is older than the max age.
+func (b *FileBackend) flushStale() {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.closed || b.lines == 0 || b.clock.Now().Sub(b.oldest) < b.maxAge {
+		return
+	}
+
+	if err := b.flush(); err != nil {
+		logInternal(ERROR, err)
+	}
+}
+
+//startFlusher is an internal method used to start up the Goroutine that
+//periodically checks if the buffered lines have gone stale.
+func (b *FileBackend) startFlusher() {
+	interval := b.maxAge / 2
+	if interval <= 0 {
+		interval = b.maxAge
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			b.flushStale()
+		case <-b.stop:
+			return
+		}
+	}
+}
+
+//backupTimeFormat is the format of the timestamp suffix of rotated log files.
+const backupTimeFormat = "2006-01-02T15-04-05.000000000"
+
+//openLogFile is an internal function that opens the log file at the
+//specified path for appending, creating it if it does not exist.
+func openLogFile(path string) (*os.File, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("File Backend: unable to open log file: %s", err)
+	}
+	return file, nil
+}
+
+//fileSize is an internal function that returns the size of the specified
+//file, or 0 if it can't be determined.
+func fileSize(file *os.File) int64 {
+	info, err := file.Stat()
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+//fileExists checks if a file exists at the specified path and returns
+//true or false based on that check.
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,405 @@
+package lumberjack
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+//tempLogPath returns a path to a log file within a new temporary directory,
+//along with a function to clean up the directory.
+func tempLogPath(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "lumberjack")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return filepath.Join(dir, "test.log"), func() { os.RemoveAll(dir) }
+}
+
+//readLines returns the non-empty lines of the file at the specified path.
+func readLines(t *testing.T, path string) []string {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := strings.TrimSpace(string(data))
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+func TestFileBackendUnbuffered(t *testing.T) {
+	path, cleanup := tempLogPath(t)
+	defer cleanup()
+
+	backend, err := NewFileBackend(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer backend.Close()
+
+	entry := testobj.Entries[0]
+	backend.Log(&entry)
+
+	lines := readLines(t, path)
+	expect(t, len(lines), 1)
+	expect(t, strings.HasSuffix(lines[0], "(ERROR) @ main.main()() main.go:10: Test Error"), true)
+}
+
+func TestFileBackendFlushOnCount(t *testing.T) {
+	path, cleanup := tempLogPath(t)
+	defer cleanup()
+
+	backend, err := NewBufferedFileBackend(path, 3, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer backend.Close()
+
+	entry := testobj.Entries[1]
+	backend.Log(&entry)
+	backend.Log(&entry)
+	expect(t, len(readLines(t, path)), 0)
+
+	// The third line fills the buffer.
+	backend.Log(&entry)
+	expect(t, len(readLines(t, path)), 3)
+}
+
+func TestFileBackendFlushOnAge(t *testing.T) {
+	path, cleanup := tempLogPath(t)
+	defer cleanup()
+
+	backend, err := NewBufferedFileBackend(path, 100, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer backend.Close()
+
+	clock := newFakeClock()
+	backend.Lock()
+	backend.clock = clock
+	backend.Unlock()
+
+	entry := testobj.Entries[1]
+	backend.Log(&entry)
+
+	clock.Advance(30 * time.Second)
+	backend.flushStale()
+	expect(t, len(readLines(t, path)), 0)
+
+	clock.Advance(31 * time.Second)
+	backend.flushStale()
+	expect(t, len(readLines(t, path)), 1)
+}
+
+func TestFileBackendFlushAndClose(t *testing.T) {
+	path, cleanup := tempLogPath(t)
+	defer cleanup()
+
+	backend, err := NewBufferedFileBackend(path, 100, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry := testobj.Entries[1]
+	backend.Log(&entry)
+	expect(t, len(readLines(t, path)), 0)
+
+	if err := backend.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, len(readLines(t, path)), 1)
+
+	backend.Log(&entry)
+	if err := backend.Close(); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, len(readLines(t, path)), 2)
+
+	// Double Close is safe, and logging after Close is a no-op.
+	if err := backend.Close(); err != nil {
+		t.Fatal(err)
+	}
+	backend.Log(&entry)
+	expect(t, len(readLines(t, path)), 2)
+}
+
+func TestFileBackendOpenError(t *testing.T) {
+	path, cleanup := tempLogPath(t)
+	defer cleanup()
+
+	_, err := NewFileBackend(filepath.Join(path, "missing", "test.log"))
+	if err == nil {
+		t.Error("Expected an error opening a file in a missing directory")
+	}
+}
+
+func TestFileBackendRotate(t *testing.T) {
+	path, cleanup := tempLogPath(t)
+	defer cleanup()
+
+	backend, err := NewFileBackend(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer backend.Close()
+
+	clock := newFakeClock()
+	backend.Lock()
+	backend.clock = clock
+	backend.Unlock()
+
+	entry := testobj.Entries[1]
+	backend.Log(&entry)
+	backend.Log(&entry)
+
+	if err := backend.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The old lines were moved to a timestamped backup.
+	backup := path + "." + clock.Now().Format(backupTimeFormat)
+	expect(t, len(readLines(t, backup)), 2)
+	expect(t, len(readLines(t, path)), 0)
+
+	// New lines go to the fresh file.
+	backend.Log(&entry)
+	expect(t, len(readLines(t, path)), 1)
+	expect(t, len(readLines(t, backup)), 2)
+
+	// Rotating again at the same time doesn't clobber the first backup.
+	if err := backend.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, len(readLines(t, backup)), 2)
+	expect(t, len(readLines(t, backup+".1")), 1)
+}
+
+func TestFileBackendRotateConcurrent(t *testing.T) {
+	path, cleanup := tempLogPath(t)
+	defer cleanup()
+
+	backend, err := NewBufferedFileBackend(path, 10, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const writers, perWriter = 4, 100
+
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			entry := testobj.Entries[1]
+			for i := 0; i < perWriter; i++ {
+				backend.Log(&entry)
+			}
+		}()
+	}
+
+	for i := 0; i < 5; i++ {
+		if err := backend.Rotate(); err != nil {
+			t.Error(err)
+		}
+	}
+
+	wg.Wait()
+	backend.Close()
+
+	// Every line ends up in exactly one of the files.
+	matches, err := filepath.Glob(path + "*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	total := 0
+	for _, match := range matches {
+		total += len(readLines(t, match))
+	}
+	expect(t, total, writers*perWriter)
+}
+
+func TestFileBackendFormatter(t *testing.T) {
+	path, cleanup := tempLogPath(t)
+	defer cleanup()
+
+	backend, err := NewFileBackend(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer backend.Close()
+	backend.Formatter = &Syslog5424Formatter{Hostname: "web-1", clock: newFakeClock()}
+
+	entry := testobj.Entries[0]
+	backend.Log(&entry)
+
+	expect(t, readLines(t, path), []string{"<3>1 2015-08-17T12:23:57.000000Z web-1 - - - - Test Error"})
+}
+
+func TestFileBackendAtomicAppend(t *testing.T) {
+	path, cleanup := tempLogPath(t)
+	defer cleanup()
+
+	const writers, perWriter, width = 4, 200, 400
+
+	// Each writer opens the file separately, as separate processes would.
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		backend, err := NewFileBackend(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		backend.AtomicAppend = true
+
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			defer backend.Close()
+			entry := testobj.Entries[1]
+			entry.Message = strings.Repeat(string(rune('a'+w)), width)
+			for i := 0; i < perWriter; i++ {
+				backend.Log(&entry)
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	lines := readLines(t, path)
+	expect(t, len(lines), writers*perWriter)
+	for _, line := range lines {
+		message := strings.Repeat(line[len(line)-1:], width)
+		if !strings.HasSuffix(line, "(INFO) @ main.main()(): "+message) {
+			t.Fatalf("Line was interleaved: %q", line)
+		}
+	}
+}
+
+func TestFileBackendMaxSize(t *testing.T) {
+	path, cleanup := tempLogPath(t)
+	defer cleanup()
+
+	backend, err := NewFileBackend(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer backend.Close()
+
+	clock := newFakeClock()
+	backend.Lock()
+	backend.clock = clock
+	backend.MaxSizeBytes = 100
+	backend.Unlock()
+
+	entry := testobj.Entries[1]
+	entry.Message = strings.Repeat("x", 40)
+
+	// Each line is over 60 bytes, so every second line rotates the file.
+	for i := 0; i < 6; i++ {
+		backend.Log(&entry)
+		clock.Advance(time.Second)
+	}
+
+	backups, err := backend.backups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, len(backups), 3)
+	for _, backup := range backups {
+		expect(t, len(readLines(t, backup)), 2)
+	}
+	expect(t, len(readLines(t, path)), 0)
+}
+
+func TestFileBackendMaxBackups(t *testing.T) {
+	path, cleanup := tempLogPath(t)
+	defer cleanup()
+
+	backend, err := NewFileBackend(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer backend.Close()
+
+	clock := newFakeClock()
+	backend.Lock()
+	backend.clock = clock
+	backend.MaxSizeBytes = 100
+	backend.MaxBackups = 2
+	backend.Unlock()
+
+	// Files that aren't rotated log files are left alone.
+	other := path + ".lock"
+	if err := ioutil.WriteFile(other, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entry := testobj.Entries[1]
+	entry.Message = strings.Repeat("x", 40)
+
+	for i := 0; i < 10; i++ {
+		backend.Log(&entry)
+		clock.Advance(time.Second)
+	}
+
+	// Five rotations happened, and only the two newest backups remain.
+	backups, err := backend.backups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, backups, []string{
+		path + "." + clock.Now().Add(-3*time.Second).Format(backupTimeFormat),
+		path + "." + clock.Now().Add(-time.Second).Format(backupTimeFormat),
+	})
+	expect(t, fileExists(other), true)
+}
+
+func TestFileBackendBackupsOrder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lumberjack")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	//Glob pattern characters in the path are matched literally.
+	path := filepath.Join(dir, "app[1]*.log")
+
+	backend, err := NewFileBackend(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer backend.Close()
+
+	stamp := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC).Format(backupTimeFormat)
+	earlier := time.Date(2020, 1, 2, 3, 4, 4, 0, time.UTC).Format(backupTimeFormat)
+
+	want := []string{
+		path + "." + earlier,
+		path + "." + stamp,
+		path + "." + stamp + ".2",
+		path + "." + stamp + ".10",
+	}
+
+	//Written out of order, along with files that aren't rotated log files.
+	for _, name := range append([]string{
+		path + "." + stamp + ".10",
+		path + "." + stamp + ".x",
+		path + ".lock",
+		filepath.Join(dir, "app1x.log."+stamp),
+	}, want[:3]...) {
+		if err := ioutil.WriteFile(name, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	backups, err := backend.backups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, backups, want)
+}
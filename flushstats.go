@@ -0,0 +1,71 @@
+package lumberjack
+
+import "sync/atomic"
+
+//FlushStats holds the statistics of the sends of a buffering network backend,
+//with the number of sends triggered by the buffer filling up, by the interval
+//passing, and by anything else such as Flush, along with the average fraction
+//of the buffer that was filled when sent. These help with tuning the buffer
+//size and interval of the backend.
+type FlushStats struct {
+	CountFlushes uint64  `json:"count_flushes"`
+	TimerFlushes uint64  `json:"timer_flushes"`
+	OtherFlushes uint64  `json:"other_flushes"`
+	AverageFill  float64 `json:"average_fill"`
+}
+
+//flushTrigger is the reason a buffer was sent.
+type flushTrigger int
+
+//Constants used to define the flushTriggers.
+const (
+	flushByCount flushTrigger = iota
+	flushByTimer
+	flushByOther
+)
+
+//flushStats counts the sends of a buffering network backend, safe for
+//concurrent use.
+type flushStats struct {
+	count   uint64
+	timer   uint64
+	other   uint64
+	entries uint64
+}
+
+//record counts a send of the specified number of buffered LogEntry objects
+//for the specified reason. Empty buffers aren't sent, so aren't counted.
+func (s *flushStats) record(trigger flushTrigger, entries int) {
+	if entries == 0 {
+		return
+	}
+
+	switch trigger {
+	case flushByCount:
+		atomic.AddUint64(&s.count, 1)
+	case flushByTimer:
+		atomic.AddUint64(&s.timer, 1)
+	default:
+		atomic.AddUint64(&s.other, 1)
+	}
+	atomic.AddUint64(&s.entries, uint64(entries))
+}
+
+//stats returns the FlushStats of a backend with the specified buffer size.
+func (s *flushStats) stats(bufsize int) FlushStats {
+	stats := FlushStats{
+		CountFlushes: atomic.LoadUint64(&s.count),
+		TimerFlushes: atomic.LoadUint64(&s.timer),
+		OtherFlushes: atomic.LoadUint64(&s.other),
+	}
+
+	if bufsize < 1 {
+		bufsize = 1 //Unbuffered, each LogEntry fills the buffer.
+	}
+
+	flushes := stats.CountFlushes + stats.TimerFlushes + stats.OtherFlushes
+	if flushes > 0 {
+		stats.AverageFill = float64(atomic.LoadUint64(&s.entries)) / float64(flushes*uint64(bufsize))
+	}
+	return stats
+}
@@ -0,0 +1,66 @@
+package lumberjack
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHttpBackendStatsCount(t *testing.T) {
+	server, count := countingServer(t)
+	defer server.Close()
+
+	hb := NewHttpClientBackend(server.URL, 4, time.Hour)
+	defer close(hb.Stop)
+
+	entry := testobj.Entries[0]
+	for i := 0; i < 10; i++ {
+		hb.Log(&entry)
+	}
+
+	// Wait for the full buffers to be sent before flushing the rest.
+	waitForCount(t, count, 8)
+	if err := hb.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, count(), 10)
+
+	// Two full buffers of 4, then Flush sent the remaining 2.
+	expect(t, hb.Stats(), FlushStats{CountFlushes: 2, OtherFlushes: 1, AverageFill: 10.0 / 12.0})
+}
+
+func TestHttpBackendStatsTimer(t *testing.T) {
+	server, count := countingServer(t)
+	defer server.Close()
+
+	hb := NewHttpClientBackend(server.URL, 10, 10*time.Millisecond)
+	defer close(hb.Stop)
+
+	entry := testobj.Entries[0]
+	hb.Log(&entry)
+	hb.Log(&entry)
+
+	waitForCount(t, count, 2)
+
+	// Empty buffers sent by the timer aren't counted.
+	stats := hb.Stats()
+	expect(t, stats.TimerFlushes > 0, true)
+	expect(t, stats.CountFlushes, uint64(0))
+	expect(t, stats.AverageFill > 0 && stats.AverageFill <= 0.2, true)
+}
+
+func TestHttpBackendStatsSync(t *testing.T) {
+	hb := NewHttpClientBackendSync("http://127.0.0.1:0")
+	expect(t, hb.Stats(), FlushStats{})
+}
+
+//waitForCount waits for the specified count function to reach the specified
+//count, failing the test if it takes too long.
+func waitForCount(t *testing.T, count func() int, want int) {
+	deadline := time.Now().Add(5 * time.Second)
+	for count() < want {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected count to reach %d, got %d", want, count())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
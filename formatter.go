@@ -0,0 +1,8 @@
+package lumberjack
+
+//Formatter is the interface used to render a LogEntry into the bytes written
+//out by a backend, allowing a backend's output to be shaped for a particular
+//downstream system, such as with the ECSFormatter.
+type Formatter interface {
+	Format(entry *LogEntry) ([]byte, error)
+}
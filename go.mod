@@ -1,5 +1,3 @@
 module github.com/btnmasher/lumberjack
 
 go 1.14
-
-require github.com/gorilla/http v0.0.0-20190627042742-d268792bdee7
@@ -2,31 +2,219 @@ package lumberjack
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
-
-	"github.com/gorilla/http"
 )
 
 //HttpClientBackend is an object that holds the configuration data
 //to be used for implementing an instance of an HTTP POST logging
 //backend that sends LogEntry messages via JSON to a specified URL.
 //
-//The exported Stop channel should be used during cleanup code
-//to close down the internal Goroutine of the HttpClientBackend.
+//Close should be called during cleanup code to send any buffered LogEntry
+//objects and close down the internal Goroutine of the HttpClientBackend.
+//Closing the exported Stop channel does the same without waiting. Once
+//stopped, any LogEntry sent to the HttpClientBackend is silently dropped.
 type HttpClientBackend struct {
-	logchan chan LogEntry
-	Stop    chan struct{}
-	timer   *time.Ticker
-	//TODO: Add more options like cookie, client certificate, basic auth, etc.
+	flushes   flushStats //First for 64-bit alignment of its atomic counters.
+	dropped   uint64
+	overflow  OverflowPolicy
+	bufsize   int
+	logchan   chan LogEntry
+	flushchan chan chan error
+	urlchan   chan urlChange
+	jobs      chan sendJob
+	Stop      chan struct{}
+	done      chan struct{}
+	timer     *time.Ticker
+	compact   int32
+	stopped   int32
+	namer     atomic.Value
+	formatter atomic.Value
+	spill     *spillBuffer
+	failover  *failover
+	retry     retryPolicy
+	request   requestConfig
+
+	synchronous bool
+	url         string
+	sendLock    sync.Mutex
+	//TODO: Add more options like cookie, client certificate, etc.
+}
+
+//requestConfig holds the configuration of the HTTP POST requests sent by an
+//HttpClientBackend, as set by the BasicAuth, Header, and RequestTimeout
+//HttpOption functions.
+type requestConfig struct {
+	header    http.Header
+	basicAuth bool
+	username  string
+	password  string
+	timeout   time.Duration
+}
+
+//DefaultHttpTimeout is the time.Duration an HTTP POST request sent by an
+//HttpClientBackend may take before it is abandoned, unless another is set
+//with RequestTimeout.
+const DefaultHttpTimeout = time.Second * 10
+
+//timeoutOrDefault returns the configured timeout of the request, or
+//DefaultHttpTimeout if none is configured.
+func (c *requestConfig) timeoutOrDefault() time.Duration {
+	if c == nil || c.timeout <= 0 {
+		return DefaultHttpTimeout
+	}
+	return c.timeout
+}
+
+//apply sets the configured headers and credentials on the specified request.
+func (c *requestConfig) apply(req *http.Request) {
+	for key, values := range c.header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	if c.basicAuth {
+		req.SetBasicAuth(c.username, c.password)
+	}
 }
 
 //logbuffer is a structure to be used to encapsulate LogEntry objects
 //in an slice so that when Marshalled into JSON will be contained
 //within a JSON array.
+//
+//If compact is set, the caller information of each LogEntry is reduced to
+//a single "src" field when Marshalled. If namer is set, the keys of each
+//LogEntry are renamed with it when Marshalled. If formatter is set, it renders
+//each LogEntry instead. If request is set, it configures the HTTP POST
+//request the logbuffer is sent with.
 type logbuffer struct {
-	Entries []LogEntry `json:"logentries"`
+	Entries   []LogEntry `json:"logentries"`
+	compact   bool
+	namer     FieldNamer
+	formatter Formatter
+	request   *requestConfig
+}
+
+//HttpOption is a function used to configure optional behavior of an
+//HttpClientBackend when passed to NewHttpClientBackend.
+type HttpOption func(*HttpClientBackend)
+
+//SpillBuffer returns an HttpOption that enables holding LogEntry objects that
+//fail to be sent in a bounded in-memory spill buffer, rather than dropping
+//them. The spilled LogEntry objects are replayed oldest-first ahead of newer
+//ones on the next successful send. Once the spill buffer holds the specified
+//capacity, the oldest LogEntry objects are dropped to make room.
+func SpillBuffer(capacity int) HttpOption {
+	return func(h *HttpClientBackend) {
+		if capacity > 0 {
+			h.spill = newSpillBuffer(capacity)
+		}
+	}
+}
+
+//BasicAuth returns an HttpOption that authenticates every HTTP POST request
+//with HTTP basic authentication using the specified username and password,
+//such as for a protected log ingestion endpoint.
+func BasicAuth(username, password string) HttpOption {
+	return func(h *HttpClientBackend) {
+		h.request.basicAuth = true
+		h.request.username = username
+		h.request.password = password
+	}
+}
+
+//Header returns an HttpOption that adds the specified header to every HTTP
+//POST request, such as an API key expected by a log ingestion endpoint. It may
+//be passed more than once to add several headers, or several values of one.
+func Header(key, value string) HttpOption {
+	return func(h *HttpClientBackend) {
+		if h.request.header == nil {
+			h.request.header = http.Header{}
+		}
+		h.request.header.Add(key, value)
+	}
+}
+
+//RequestTimeout returns an HttpOption that sets the time.Duration an HTTP POST
+//request may take before it is abandoned, so that a hung endpoint can't back up
+//the LogEntry objects waiting to be sent. An abandoned request fails like any
+//other, so the error is logged internally and the LogEntry objects are dropped,
+//or spilled if SpillBuffer is enabled. If no timeout is specified, the default
+//of DefaultHttpTimeout will be chosen.
+func RequestTimeout(timeout time.Duration) HttpOption {
+	return func(h *HttpClientBackend) {
+		h.request.timeout = timeout
+	}
+}
+
+//OverflowPolicy decides what an HttpClientBackend does with a LogEntry sent
+//while its channel of LogEntry objects waiting to be buffered is full, such as
+//while the HTTP endpoint is stalled.
+type OverflowPolicy int
+
+//Constants used to define the OverflowPolicy options. DropNewest is the
+//default, so that logging never blocks the application.
+const (
+	DropNewest OverflowPolicy = iota //Drop the LogEntry being sent.
+	DropOldest                       //Drop the oldest waiting LogEntry to make room.
+	Block                            //Wait for room, blocking the caller.
+)
+
+//Overflow returns an HttpOption that sets the OverflowPolicy used when a
+//LogEntry is sent while the channel of LogEntry objects waiting to be buffered
+//is full. Every LogEntry dropped is counted, as returned by Dropped.
+func Overflow(policy OverflowPolicy) HttpOption {
+	return func(h *HttpClientBackend) {
+		h.overflow = policy
+	}
+}
+
+//retryPolicy holds the number of times a failed send is retried by an
+//HttpClientBackend, and the base backoff between the attempts.
+type retryPolicy struct {
+	maxRetries int
+	backoff    time.Duration
+}
+
+//Retry returns an HttpOption that enables retrying a send that fails with a
+//transient error, being a failure to reach the url or a 5xx or 429 response,
+//up to the specified maximum number of retries before the LogEntry objects are
+//dropped, or spilled if SpillBuffer is enabled. The specified backoff
+//time.Duration is waited before the first retry, and doubles with each one
+//after. If no backoff is specified, a default of 1 second will be chosen.
+//
+//Sends happen on a separate Goroutine, so LogEntry objects keep being accepted
+//and buffered while a send is being retried.
+func Retry(maxRetries int, backoff time.Duration) HttpOption {
+	return func(h *HttpClientBackend) {
+		if backoff == 0 {
+			backoff = time.Second * 1
+		}
+		h.retry = retryPolicy{maxRetries: maxRetries, backoff: backoff}
+	}
+}
+
+//Failover returns an HttpOption that enables sending to the specified
+//fallback urls when the primary url fails, in order of preference. A failed
+//url is skipped until the specified backoff time.Duration has passed, which
+//doubles with each consecutive failure, and the primary url is returned to
+//as soon as it's healthy again. If no backoff is specified, a default of 1
+//second will be chosen.
+func Failover(backoff time.Duration, urls ...string) HttpOption {
+	return func(h *HttpClientBackend) {
+		if backoff == 0 {
+			backoff = time.Second * 1
+		}
+		h.failover = newFailover(backoff, urls)
+	}
 }
 
 //NewHttpClientBackend is a function that accepts the url string, LogEntry buffer size
@@ -41,33 +229,81 @@ type logbuffer struct {
 //will keep slowly moving logs from sitting too long in the buffer. If no interval
 //is specified, a default of 1 second will be chosen. If no bufsize is specified,
 //each LogEntry will be sent via HTTP POST individually.
-func NewHttpClientBackend(url string, bufsize int, interval time.Duration) *HttpClientBackend {
+//
+//Any optional behavior can be configured by passing HttpOption functions.
+func NewHttpClientBackend(url string, bufsize int, interval time.Duration, opts ...HttpOption) *HttpClientBackend {
 	if interval == 0 {
 		interval = time.Second * 1 //Default to 1 second interval incase they decided to be a poo-head and not set it.
 	}
 
 	h := HttpClientBackend{
-		logchan: make(chan LogEntry, 50),  //Some breathing room to keep from blocking
-		Stop:    make(chan struct{}),      //So we can kill our goroutine cleanly, implementer must close(h.Stop)
-		timer:   time.NewTicker(interval), //how often we want to clear the buffer if not full.
+		logchan:   make(chan LogEntry, 50),  //Some breathing room to keep from blocking
+		flushchan: make(chan chan error),    //So Flush can wait on the goroutine to send the buffer
+		urlchan:   make(chan urlChange),     //So SetURL can switch the url between sends
+		jobs:      make(chan sendJob, 4),    //Some breathing room while a send is retried
+		Stop:      make(chan struct{}),      //So we can kill our goroutine cleanly, implementer must close(h.Stop)
+		done:      make(chan struct{}),      //Closed once the goroutine has exited
+		timer:     time.NewTicker(interval), //how often we want to clear the buffer if not full.
+		bufsize:   bufsize,
 	}
 
-	go startClient(url, bufsize, h.logchan, h.timer, h.Stop)
+	for _, opt := range opts {
+		opt(&h)
+	}
+
+	if h.failover != nil {
+		h.failover.setPrimary(url)
+	}
+
+	go startClient(url, bufsize, &h)
+
+	return &h
+}
+
+//NewHttpClientBackendSync is a function that accepts the url string to be used to
+//configure a new instance of HttpClientBackend that sends each LogEntry via HTTP POST
+//synchronously within the call to Log, with no Goroutine and no buffering. This is
+//intended for tests and low volume applications that prefer simplicity over throughput,
+//as every call to Log blocks until the HTTP request has completed.
+//
+//Any optional behavior can be configured by passing HttpOption functions.
+func NewHttpClientBackendSync(url string, opts ...HttpOption) *HttpClientBackend {
+	h := HttpClientBackend{
+		Stop:        make(chan struct{}),
+		synchronous: true,
+		url:         url,
+	}
+
+	for _, opt := range opts {
+		opt(&h)
+	}
+
+	if h.failover != nil {
+		h.failover.setPrimary(url)
+	}
 
 	return &h
 }
 
 //startClient is an internal function used by the NewHttpClientBackend function to start up
 //the Goroutine that will be ultimately handling the buffered LogEntry messages and
-//sending via HTTP POST as JSON.
-func startClient(url string, bufsize int, logchan chan LogEntry, timer *time.Ticker, stop chan struct{}) {
+//sending via HTTP POST as JSON. The buffers are handed off to be sent by a separate
+//worker Goroutine, in order, so that slow or retried sends don't hold up buffering.
+//
+//Once the Stop channel is closed, any LogEntry messages still waiting are
+//sent before the Goroutine exits.
+func startClient(url string, bufsize int, h *HttpClientBackend) {
 	var buffer logbuffer
 
-	defer timer.Stop()
+	sent := make(chan struct{})
+	go h.startSender(sent)
+
+	defer close(h.done)
+	defer h.timer.Stop()
 
 	for {
 		select {
-		case entry := <-logchan:
+		case entry := <-h.logchan:
 			buffer.Entries = append(buffer.Entries, entry)
 
 			if bufsize > 0 { //Are we even trying to buffer requests?
@@ -76,48 +312,434 @@ func startClient(url string, bufsize int, logchan chan LogEntry, timer *time.Tic
 				}
 			}
 
-			err := doSend(url, buffer) //Send that buffer!
-			if err != nil {
-				logInternal(ERROR, err)
+			h.flushes.record(flushByCount, len(buffer.Entries))
+			h.enqueue(url, &buffer, nil) //Send that buffer!
+
+		case <-h.timer.C:
+			h.flushes.record(flushByTimer, len(buffer.Entries))
+			if len(buffer.Entries) == 0 && h.spill == nil {
+				continue //Nothing to send, and nothing spilled to replay.
 			}
-			buffer.Entries = buffer.Entries[:0] //Clear that buffer!
+			h.enqueue(url, &buffer, nil) //Time's up, send what we have!
 
-		case <-timer.C:
-			if len(buffer.Entries) > 0 {
-				err := doSend(url, buffer)
-				if err != nil {
-					logInternal(ERROR, err)
-				}
-				buffer.Entries = buffer.Entries[:0] //Time's up, send what we have!
+		case done := <-h.flushchan:
+			h.drain(&buffer)
+			h.flushes.record(flushByOther, len(buffer.Entries))
+			h.enqueue(url, &buffer, done)
+
+		case change := <-h.urlchan:
+			h.drain(&buffer)
+			h.flushes.record(flushByOther, len(buffer.Entries))
+			h.enqueue(url, &buffer, change.done) //Everything logged so far goes to the old url.
+			url = change.url
+			if h.failover != nil {
+				h.failover.replacePrimary(url)
 			}
 
-		case <-stop:
-			break
+		case <-h.Stop:
+			atomic.StoreInt32(&h.stopped, 1)
+			h.drain(&buffer)
+			h.flushes.record(flushByOther, len(buffer.Entries))
+			h.enqueue(url, &buffer, nil)
+			close(h.jobs)
+			<-sent
+			return
 		}
 	}
 }
 
+//sendJob is a buffer of LogEntry objects handed off to the worker Goroutine of
+//an HttpClientBackend to be sent to a url. If done is set, the result of the
+//send is returned on it rather than logged internally.
+type sendJob struct {
+	url    string
+	buffer logbuffer
+	done   chan error
+}
+
+//enqueue is an internal method used by the Goroutine to hand off the buffered
+//LogEntry objects to the worker Goroutine to be sent to the specified url,
+//leaving the buffer empty.
+func (h *HttpClientBackend) enqueue(url string, buffer *logbuffer, done chan error) {
+	h.jobs <- sendJob{url: url, buffer: logbuffer{Entries: buffer.Entries}, done: done}
+	buffer.Entries = nil //The worker owns those now, start a fresh buffer.
+}
+
+//startSender is an internal method used to start up the worker Goroutine that
+//sends each buffer handed off to it in order, and closes the specified channel
+//once the jobs channel is closed and every buffer has been sent.
+func (h *HttpClientBackend) startSender(sent chan struct{}) {
+	defer close(sent)
+
+	for job := range h.jobs {
+		err := h.send(job.url, &job.buffer)
+		if job.done != nil {
+			job.done <- err
+		} else if err != nil {
+			logInternal(ERROR, err)
+		}
+	}
+}
+
+//drain is an internal method used by the Goroutine to pick up any LogEntry
+//messages still waiting in the channel so they aren't left behind.
+func (h *HttpClientBackend) drain(buffer *logbuffer) {
+	for {
+		select {
+		case entry := <-h.logchan:
+			buffer.Entries = append(buffer.Entries, entry)
+		default:
+			return
+		}
+	}
+}
+
+//send is an internal method used by the Goroutine to send the buffered
+//LogEntry objects, preceded by any spilled by previous failures, via HTTP POST
+//to the specified url, then clear the buffer. If sending fails and spilling
+//is enabled, the buffered LogEntry objects are spilled to be replayed later.
+func (h *HttpClientBackend) send(url string, buffer *logbuffer) error {
+	batch := logbuffer{
+		Entries: buffer.Entries,
+		compact: atomic.LoadInt32(&h.compact) == 1,
+		request: &h.request,
+	}
+	batch.namer, _ = h.namer.Load().(FieldNamer)
+	if holder, ok := h.formatter.Load().(formatterHolder); ok {
+		batch.formatter = holder.Formatter
+	}
+
+	if h.spill != nil && len(h.spill.entries()) > 0 {
+		batch.Entries = append(append([]LogEntry(nil), h.spill.entries()...), buffer.Entries...)
+	}
+
+	if len(batch.Entries) == 0 {
+		return nil //Nothing to send.
+	}
+
+	err := h.sendWithRetry(url, batch)
+
+	if h.spill != nil {
+		if err != nil {
+			h.spill.add(buffer.Entries)
+		} else {
+			h.spill.replay()
+		}
+	}
+
+	buffer.Entries = buffer.Entries[:0] //Clear that buffer!
+	return err
+}
+
+//sendWithRetry is an internal method that sends the specified logbuffer to the
+//specified url, or the failover urls if Failover is enabled, retrying with an
+//exponential backoff if the send fails with a transient error and Retry is
+//enabled. Retrying is cut short if the Stop channel is closed.
+func (h *HttpClientBackend) sendWithRetry(url string, batch logbuffer) error {
+	for attempt := 0; ; attempt++ {
+		var err error
+		if h.failover != nil {
+			err = h.failover.send(batch)
+		} else {
+			err = doSend(url, batch)
+		}
+
+		if err == nil || attempt >= h.retry.maxRetries || !isTransient(err) {
+			return err
+		}
+
+		shift := attempt
+		if shift > maxBackoffShift {
+			shift = maxBackoffShift
+		}
+		select {
+		case <-time.After(h.retry.backoff << uint(shift)):
+		case <-h.Stop:
+			return err
+		}
+	}
+}
+
+//transientError is an error sending a logbuffer that may not recur if the
+//send is retried, such as a failure to connect or a 5xx response.
+type transientError struct {
+	error
+}
+
+//isTransient checks if the specified error is a transientError.
+func isTransient(err error) bool {
+	_, ok := err.(transientError)
+	return ok
+}
+
+//maxErrorBodyBytes is the maximum number of bytes of the body of a non-2xx
+//response included in the error returned by doSend.
+const maxErrorBodyBytes = 512
+
 //doSend is an internal function that accepts a url and a logbuffer object that
 //contains LogEntry objects to be Marshalled to JSON then sent via HTTP POST
-//to the specified url. It returns an error if the http reqeust fails.
+//to the specified url. It returns an error if the http reqeust fails, if it
+//takes longer than the timeout of the request configuration of the logbuffer,
+//or if the response has a non-2xx status, including the start of the body of
+//the response in the error.
 func doSend(url string, buffer logbuffer) error {
-	data, err := json.Marshal(buffer)
+	data, err := marshalBuffer(buffer)
 	if err != nil {
 		return fmt.Errorf("HTTP Backend: unable to Marshal JSON from logbuffer struct: %s", err)
 	}
 
-	b := bytes.NewBuffer(data)
+	ctx, cancel := context.WithTimeout(context.Background(), buffer.request.timeoutOrDefault())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("HTTP Backend: unable to create request for specified URL: %s", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if buffer.request != nil {
+		buffer.request.apply(req)
+	}
 
-	err = http.Post(url, b)
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("HTTP Backend: unable to POST to specified URL, library returned error: %s", err)
+		return transientError{fmt.Errorf("HTTP Backend: unable to POST to specified URL, library returned error: %s", err)}
+	}
+	defer resp.Body.Close()
+	snippet, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+	io.Copy(ioutil.Discard, resp.Body) //Read it all so the connection can be reused.
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		err := fmt.Errorf("HTTP Backend: unable to POST to specified URL, server responded with status: %s", resp.Status)
+		if body := strings.TrimSpace(string(snippet)); body != "" {
+			err = fmt.Errorf("%s: %s", err, body)
+		}
+		if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+			return transientError{err}
+		}
+		return err
 	}
 	return nil
 }
 
+//marshalBuffer is an internal function that Marshals a logbuffer object into
+//JSON. If the logbuffer as a whole fails to Marshal, each LogEntry is instead
+//Marshalled individually, and any LogEntry that fails is replaced with a
+//sanitized representation so that a single bad LogEntry does not cause the
+//entire buffer to be lost. If the logbuffer has a FieldNamer or Formatter, each
+//LogEntry is always Marshalled individually so that it can be renamed or
+//rendered by it.
+func marshalBuffer(buffer logbuffer) ([]byte, error) {
+	if buffer.namer == nil && buffer.formatter == nil {
+		payload := struct {
+			Entries []interface{} `json:"logentries"`
+		}{}
+
+		for i := range buffer.Entries {
+			payload.Entries = append(payload.Entries, entryPayload(&buffer.Entries[i], buffer.compact))
+		}
+
+		data, err := json.Marshal(payload)
+		if err == nil {
+			return data, nil
+		}
+	}
+
+	fallback := struct {
+		Entries []json.RawMessage `json:"logentries"`
+	}{}
+
+	for i := range buffer.Entries {
+		var raw []byte
+		var err error
+		if buffer.formatter != nil {
+			raw, err = buffer.formatter.Format(&buffer.Entries[i])
+		} else {
+			raw, err = marshalEntryNamed(&buffer.Entries[i], buffer.compact, buffer.namer)
+		}
+		if err != nil {
+			return nil, err
+		}
+		fallback.Entries = append(fallback.Entries, raw)
+	}
+
+	return json.Marshal(fallback)
+}
+
+//Flush sends any LogEntry objects buffered by the current HttpClientBackend
+//via HTTP POST immediately, and returns once the request has completed.
+func (h *HttpClientBackend) Flush() error {
+	if h.synchronous {
+		return nil //Nothing is ever buffered.
+	}
+
+	done := make(chan error)
+	select {
+	case h.flushchan <- done:
+		return <-done
+	case <-h.done:
+		return fmt.Errorf("HTTP Backend: unable to flush, the backend has been stopped")
+	}
+}
+
+//Close sends any LogEntry objects buffered by the current HttpClientBackend,
+//then stops its internal Goroutine, returning once it has exited. Any error
+//sending the buffered LogEntry objects is returned. Once closed, any LogEntry
+//sent to the HttpClientBackend is silently dropped. Calling Close more than
+//once has no effect.
+func (h *HttpClientBackend) Close() error {
+	if !atomic.CompareAndSwapInt32(&h.stopped, 0, 1) {
+		return nil
+	}
+
+	var err error
+	if !h.synchronous {
+		err = h.Flush()
+	}
+
+	select {
+	case <-h.Stop: //Already closed by the implementer.
+	default:
+		close(h.Stop)
+	}
+
+	if !h.synchronous {
+		<-h.done
+	}
+	return err
+}
+
+//urlChange is a request sent to the Goroutine of an HttpClientBackend to
+//switch the url it sends to.
+type urlChange struct {
+	url  string
+	done chan error
+}
+
+//SetURL changes the url the current HttpClientBackend sends LogEntry objects
+//to via HTTP POST, such as to repoint it at new logging infrastructure without
+//restarting. Any LogEntry objects logged before the change are sent to the old
+//url first, and any error sending them is returned. If Failover is enabled,
+//the url replaces the primary url.
+func (h *HttpClientBackend) SetURL(url string) error {
+	if h.synchronous {
+		h.sendLock.Lock()
+		defer h.sendLock.Unlock()
+		h.url = url
+		if h.failover != nil {
+			h.failover.replacePrimary(url)
+		}
+		return nil //Nothing is ever buffered.
+	}
+
+	change := urlChange{url: url, done: make(chan error)}
+	select {
+	case h.urlchan <- change:
+		return <-change.done
+	case <-h.done:
+		return fmt.Errorf("HTTP Backend: unable to set URL, the backend has been stopped")
+	}
+}
+
+//SpillStats returns the statistics of the spill buffer of the current
+//HttpClientBackend. The zero value is returned if spilling is not enabled.
+func (h *HttpClientBackend) SpillStats() SpillStats {
+	if h.spill == nil {
+		return SpillStats{}
+	}
+	return h.spill.stats()
+}
+
+//Stats returns the FlushStats of the buffered sends of the current
+//HttpClientBackend. The zero value is returned if it was created with
+//NewHttpClientBackendSync, as nothing is buffered.
+func (h *HttpClientBackend) Stats() FlushStats {
+	return h.flushes.stats(h.bufsize)
+}
+
+//SetCompactCaller sets whether the caller information of each LogEntry sent
+//by the current HttpClientBackend is reduced to a single "src" field in the
+//"file:line" form, which significantly reduces the size of each request.
+func (h *HttpClientBackend) SetCompactCaller(enabled bool) {
+	var value int32
+	if enabled {
+		value = 1
+	}
+	atomic.StoreInt32(&h.compact, value)
+}
+
+//SetFieldNamer sets the FieldNamer used to rename the keys of each LogEntry
+//sent by the current HttpClientBackend, such as with ECSFieldNames. A nil
+//FieldNamer keeps the keys as they are.
+func (h *HttpClientBackend) SetFieldNamer(namer FieldNamer) {
+	h.namer.Store(namer)
+}
+
+//formatterHolder wraps a Formatter so that Formatters of differing concrete
+//types can be stored in the same atomic.Value.
+type formatterHolder struct {
+	Formatter
+}
+
+//SetFormatter sets the Formatter used to render each LogEntry sent by the
+//current HttpClientBackend, such as the ECSFormatter, which must render
+//JSON. A nil Formatter restores the default rendering.
+func (h *HttpClientBackend) SetFormatter(formatter Formatter) {
+	h.formatter.Store(formatterHolder{formatter})
+}
+
 //Log implements the Backend interface's requirements and will send LogEntry
 //object references to the channel on the current HttpClientBackend to be
-//buffered then sent via HTTP POST as JSON.
+//buffered then sent via HTTP POST as JSON. If the channel is full, the LogEntry
+//is handled as decided by the OverflowPolicy. If the HttpClientBackend was
+//created with NewHttpClientBackendSync, the LogEntry is sent immediately.
 func (h *HttpClientBackend) Log(entry *LogEntry) {
-	h.logchan <- *entry
+	if atomic.LoadInt32(&h.stopped) == 1 {
+		return //Silently dropped, the backend has been stopped.
+	}
+
+	if h.synchronous {
+		h.sendLock.Lock()
+		defer h.sendLock.Unlock()
+
+		buffer := logbuffer{Entries: []LogEntry{*entry}}
+		if err := h.send(h.url, &buffer); err != nil {
+			logInternal(ERROR, err)
+		}
+		return
+	}
+
+	if h.overflow == Block {
+		select {
+		case h.logchan <- *entry:
+		case <-h.done: //Stopped while waiting, drop it.
+		}
+		return
+	}
+
+	select {
+	case h.logchan <- *entry:
+		return
+	case <-h.done: //Stopped while sending, drop it.
+		return
+	default:
+	}
+
+	if h.overflow == DropOldest {
+		select {
+		case <-h.logchan: //Make room by dropping the oldest.
+		default:
+		}
+		select {
+		case h.logchan <- *entry:
+		default: //Filled again by another caller, drop this one instead.
+		}
+	}
+	atomic.AddUint64(&h.dropped, 1)
+}
+
+//Dropped returns the number of LogEntry objects dropped by the current
+//HttpClientBackend because its channel was full, as decided by the
+//OverflowPolicy.
+func (h *HttpClientBackend) Dropped() uint64 {
+	return atomic.LoadUint64(&h.dropped)
 }
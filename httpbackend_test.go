@@ -6,11 +6,15 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func expect(t *testing.T, a interface{}, b interface{}) {
-	if a != b {
+	if !reflect.DeepEqual(a, b) {
 		t.Errorf("Expected %v (type %v) - Got %v (type %v)", b, reflect.TypeOf(b), a, reflect.TypeOf(a))
 	}
 }
@@ -73,3 +77,644 @@ func TestHttpBackendPost(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestHttpBackendPostMarshalFallback(t *testing.T) {
+
+	// A batch where the middle entry has a LogLevel that can't be marshalled.
+	batch := logbuffer{
+		Entries: []LogEntry{
+			testobj.Entries[0],
+			{
+				Level:   LogLevel(200),
+				Caller:  "main.main()",
+				Path:    "/somewhere",
+				File:    "main.go",
+				Line:    12,
+				Message: "Test Bad Level",
+			},
+			testobj.Entries[1],
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+
+		// Read the entries generically since the sanitized entry won't fit a LogEntry.
+		b := struct {
+			Entries []map[string]interface{} `json:"logentries"`
+		}{}
+
+		bytes, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+
+		if err := json.Unmarshal(bytes, &b); err != nil {
+			t.Error(err)
+			return
+		}
+
+		// All 3 entries should have shipped
+		expect(t, len(b.Entries), 3)
+
+		// The good entries should be intact
+		expect(t, b.Entries[0]["message"], "Test Error")
+		expect(t, b.Entries[0]["level"], "ERROR")
+		expect(t, b.Entries[2]["message"], "Test Info")
+		expect(t, b.Entries[2]["level"], "INFO")
+
+		// The bad entry should have its level sanitized but keep the rest
+		expect(t, b.Entries[1]["message"], "Test Bad Level")
+		level, _ := b.Entries[1]["level"].(string)
+		expect(t, strings.HasPrefix(level, "!MARSHAL_ERROR("), true)
+	}))
+
+	defer server.Close()
+
+	err := doSend(server.URL, batch)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestHttpBackendPostCompactCaller(t *testing.T) {
+	batch := logbuffer{Entries: testobj.Entries, compact: true}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+
+		b := struct {
+			Entries []map[string]interface{} `json:"logentries"`
+		}{}
+
+		bytes, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+
+		if err := json.Unmarshal(bytes, &b); err != nil {
+			t.Error(err)
+			return
+		}
+
+		expect(t, len(b.Entries), 2)
+		expect(t, b.Entries[0]["src"], "main.go:10")
+		expect(t, b.Entries[1]["src"], "main.go:11")
+
+		for _, entry := range b.Entries {
+			for _, key := range []string{"caller", "path", "file", "line"} {
+				if _, exists := entry[key]; exists {
+					t.Errorf("Expected key %q to be omitted in compact output", key)
+				}
+			}
+		}
+	}))
+
+	defer server.Close()
+
+	err := doSend(server.URL, batch)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+//countingServer returns a test server that counts the LogEntry objects
+//POSTed to it, along with a function returning the current count.
+func countingServer(t *testing.T) (*httptest.Server, func() int) {
+	var mu sync.Mutex
+	count := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b := logbuffer{}
+		if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+			t.Error(err)
+		}
+
+		mu.Lock()
+		count += len(b.Entries)
+		mu.Unlock()
+
+		w.WriteHeader(200)
+	}))
+
+	return server, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return count
+	}
+}
+
+func TestHttpBackendFlush(t *testing.T) {
+	server, count := countingServer(t)
+	defer server.Close()
+
+	// Buffer large enough and interval long enough that only Flush sends.
+	hb := NewHttpClientBackend(server.URL, 100, time.Hour)
+
+	entry := testobj.Entries[0]
+	for i := 0; i < 5; i++ {
+		hb.Log(&entry)
+	}
+
+	if err := hb.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, count(), 5)
+}
+
+func TestHttpBackendReplaceDrained(t *testing.T) {
+	oldServer, oldCount := countingServer(t)
+	defer oldServer.Close()
+	newServer, newCount := countingServer(t)
+	defer newServer.Close()
+
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+
+	logger.AddBackend("http", NewHttpClientBackend(oldServer.URL, 100, time.Hour))
+	for i := 0; i < 5; i++ {
+		logger.Info("before")
+	}
+
+	replacement := NewHttpClientBackend(newServer.URL, 100, time.Hour)
+	if err := logger.ReplaceBackendDrained("http", replacement); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, oldCount(), 5)
+
+	for i := 0; i < 3; i++ {
+		logger.Info("after")
+	}
+	replacement.Flush()
+
+	expect(t, oldCount(), 5)
+	expect(t, newCount(), 3)
+}
+
+func TestHttpBackendSpillBuffer(t *testing.T) {
+	var down int32 = 1
+	var mu sync.Mutex
+	var received []string
+
+	// Test server that fails while down and records messages while up.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&down) == 1 {
+			w.WriteHeader(500)
+			return
+		}
+
+		b := logbuffer{}
+		if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+			t.Error(err)
+		}
+
+		mu.Lock()
+		for _, entry := range b.Entries {
+			received = append(received, entry.Message)
+		}
+		mu.Unlock()
+
+		w.WriteHeader(200)
+	}))
+
+	defer server.Close()
+
+	hb := NewHttpClientBackend(server.URL, 1, time.Hour, SpillBuffer(3))
+
+	// Log through the outage, only the last 3 fit in the spill buffer.
+	for _, message := range []string{"a1", "a2", "a3", "a4", "a5"} {
+		entry := LogEntry{Level: INFO, Message: message}
+		hb.Log(&entry)
+	}
+	hb.Flush()
+
+	expect(t, hb.SpillStats(), SpillStats{Spilled: 5, Dropped: 2, Pending: 3})
+
+	// Recover, and the spilled entries are replayed ahead of new ones.
+	atomic.StoreInt32(&down, 0)
+
+	entry := LogEntry{Level: INFO, Message: "b1"}
+	hb.Log(&entry)
+	hb.Flush()
+
+	mu.Lock()
+	expect(t, received, []string{"a3", "a4", "a5", "b1"})
+	mu.Unlock()
+
+	expect(t, hb.SpillStats(), SpillStats{Spilled: 5, Dropped: 2, Replayed: 3})
+}
+
+func TestHttpBackendSync(t *testing.T) {
+	server, count := countingServer(t)
+	defer server.Close()
+
+	hb := NewHttpClientBackendSync(server.URL)
+
+	// Each entry is delivered before Log returns.
+	entry := testobj.Entries[0]
+	hb.Log(&entry)
+	expect(t, count(), 1)
+
+	hb.Log(&entry)
+	expect(t, count(), 2)
+
+	expect(t, hb.Flush(), nil)
+}
+
+func TestHttpBackendStop(t *testing.T) {
+	server, count := countingServer(t)
+	defer server.Close()
+
+	// Buffer large enough and interval long enough that only stopping sends.
+	hb := NewHttpClientBackend(server.URL, 100, time.Hour)
+
+	entry := testobj.Entries[0]
+	for i := 0; i < 3; i++ {
+		hb.Log(&entry)
+	}
+
+	close(hb.Stop)
+
+	select {
+	case <-hb.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected goroutine to exit after closing Stop")
+	}
+
+	expect(t, count(), 3)
+
+	if err := hb.Flush(); err == nil {
+		t.Error("Expected error flushing a stopped backend")
+	}
+}
+
+func TestHttpBackendClose(t *testing.T) {
+	server, count := countingServer(t)
+	defer server.Close()
+
+	hb := NewHttpClientBackend(server.URL, 100, time.Hour)
+
+	entry := testobj.Entries[0]
+	for i := 0; i < 3; i++ {
+		hb.Log(&entry)
+	}
+
+	if err := hb.Close(); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, count(), 3)
+
+	// Logging after closing is a silent no-op, rather than a hang or panic.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		hb.Log(&entry)
+		hb.Close()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Log blocked on a closed backend")
+	}
+	expect(t, count(), 3)
+	expect(t, hb.Dropped(), uint64(0))
+}
+
+func TestHttpBackendLogAfterStop(t *testing.T) {
+	server, count := countingServer(t)
+	defer server.Close()
+
+	hb := NewHttpClientBackend(server.URL, 100, time.Hour, Overflow(Block))
+	close(hb.Stop)
+	<-hb.done
+
+	entry := testobj.Entries[0]
+	hb.Log(&entry)
+	expect(t, hb.Close(), nil)
+	expect(t, count(), 0)
+
+	sync := NewHttpClientBackendSync(server.URL)
+	expect(t, sync.Close(), nil)
+	sync.Log(&entry)
+	expect(t, count(), 0)
+}
+
+func TestHttpBackendSetURL(t *testing.T) {
+	oldServer, oldCount := countingServer(t)
+	defer oldServer.Close()
+	newServer, newCount := countingServer(t)
+	defer newServer.Close()
+
+	// Buffer large enough and interval long enough that nothing is sent early.
+	hb := NewHttpClientBackend(oldServer.URL, 100, time.Hour)
+	defer close(hb.Stop)
+
+	entry := testobj.Entries[0]
+	for i := 0; i < 4; i++ {
+		hb.Log(&entry)
+	}
+
+	if err := hb.SetURL(newServer.URL); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, oldCount(), 4)
+	expect(t, newCount(), 0)
+
+	for i := 0; i < 2; i++ {
+		hb.Log(&entry)
+	}
+	if err := hb.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	expect(t, oldCount(), 4)
+	expect(t, newCount(), 2)
+}
+
+func TestHttpBackendSetURLSync(t *testing.T) {
+	oldServer, oldCount := countingServer(t)
+	defer oldServer.Close()
+	newServer, newCount := countingServer(t)
+	defer newServer.Close()
+
+	hb := NewHttpClientBackendSync(oldServer.URL)
+
+	entry := testobj.Entries[0]
+	hb.Log(&entry)
+	expect(t, hb.SetURL(newServer.URL), nil)
+	hb.Log(&entry)
+
+	expect(t, oldCount(), 1)
+	expect(t, newCount(), 1)
+}
+
+func TestHttpBackendSetURLStopped(t *testing.T) {
+	hb := NewHttpClientBackend("http://127.0.0.1:0", 100, time.Hour)
+	close(hb.Stop)
+	<-hb.done
+
+	if err := hb.SetURL("http://127.0.0.1:1"); err == nil {
+		t.Error("Expected error setting the URL of a stopped backend")
+	}
+}
+
+func TestHttpBackendBasicAuthAndHeaders(t *testing.T) {
+	var mu sync.Mutex
+	var got *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		got = r
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	hb := NewHttpClientBackend(server.URL, 0, time.Hour,
+		BasicAuth("ingest", "s3cret"),
+		Header("X-Api-Key", "key-123"),
+		Header("X-Tag", "a"),
+		Header("X-Tag", "b"),
+	)
+	defer close(hb.Stop)
+
+	entry := testobj.Entries[0]
+	hb.Log(&entry)
+	if err := hb.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	user, pass, ok := got.BasicAuth()
+	expect(t, ok, true)
+	expect(t, user, "ingest")
+	expect(t, pass, "s3cret")
+	expect(t, got.Header.Get("X-Api-Key"), "key-123")
+	expect(t, got.Header["X-Tag"], []string{"a", "b"})
+	expect(t, got.Header.Get("Content-Type"), "application/json")
+}
+
+func TestHttpBackendResponseStatus(t *testing.T) {
+	tests := []struct {
+		status  int
+		body    string
+		wantErr bool
+	}{
+		{http.StatusOK, "", false},
+		{http.StatusInternalServerError, "database is down\n", true},
+		{http.StatusUnauthorized, `{"error":"invalid token"}`, true},
+	}
+
+	for _, test := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(test.status)
+			w.Write([]byte(test.body))
+		}))
+
+		err := doSend(server.URL, testobj)
+		server.Close()
+
+		if !test.wantErr {
+			expect(t, err, nil)
+			continue
+		}
+		if err == nil {
+			t.Errorf("Expected an error for status %d", test.status)
+			continue
+		}
+		expect(t, strings.Contains(err.Error(), http.StatusText(test.status)), true)
+		expect(t, strings.HasSuffix(err.Error(), strings.TrimSpace(test.body)), true)
+	}
+}
+
+func TestHttpBackendResponseBodyTruncated(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte(strings.Repeat("x", maxErrorBodyBytes*2)))
+	}))
+	defer server.Close()
+
+	err := doSend(server.URL, testobj)
+	if err == nil {
+		t.Fatal("Expected an error for a bad gateway response")
+	}
+	expect(t, strings.Count(err.Error(), "x"), maxErrorBodyBytes)
+}
+
+func TestHttpBackendRequestTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release // Hang until the test is over.
+	}))
+	defer server.Close()
+	defer close(release)
+
+	hb := NewHttpClientBackend(server.URL, 10, time.Hour, RequestTimeout(50*time.Millisecond))
+	defer close(hb.Stop)
+
+	entry := testobj.Entries[0]
+	hb.Log(&entry)
+
+	start := time.Now()
+	err := hb.Flush()
+	if err == nil {
+		t.Fatal("Expected an error for a request that timed out")
+	}
+	expect(t, strings.Contains(err.Error(), "deadline exceeded"), true)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the request to be abandoned after the timeout, took %s", elapsed)
+	}
+
+	// The backend is not wedged, and keeps sending after the timeout.
+	hb.Log(&entry)
+	expect(t, hb.Flush() != nil, true)
+}
+
+func TestRequestTimeoutDefault(t *testing.T) {
+	var config *requestConfig
+	expect(t, config.timeoutOrDefault(), DefaultHttpTimeout)
+	expect(t, (&requestConfig{}).timeoutOrDefault(), DefaultHttpTimeout)
+	expect(t, (&requestConfig{timeout: time.Second}).timeoutOrDefault(), time.Second)
+}
+
+func TestHttpBackendRetry(t *testing.T) {
+	var attempts int32
+	var mu sync.Mutex
+	var received []string
+
+	// Test server that fails the first two attempts, then succeeds.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		b := logbuffer{}
+		if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+			t.Error(err)
+		}
+
+		mu.Lock()
+		for _, entry := range b.Entries {
+			received = append(received, entry.Message)
+		}
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	hb := NewHttpClientBackend(server.URL, 10, time.Hour, Retry(3, 10*time.Millisecond))
+	defer close(hb.Stop)
+
+	for _, message := range []string{"one", "two", "three"} {
+		entry := LogEntry{Level: INFO, Message: message}
+		hb.Log(&entry)
+	}
+
+	if err := hb.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	expect(t, atomic.LoadInt32(&attempts), int32(3))
+	mu.Lock()
+	expect(t, received, []string{"one", "two", "three"})
+	mu.Unlock()
+}
+
+func TestHttpBackendRetryGivesUp(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	hb := NewHttpClientBackendSync(server.URL, Retry(2, time.Millisecond))
+	buffer := logbuffer{Entries: testobj.Entries}
+	if err := hb.send(server.URL, &buffer); err == nil {
+		t.Fatal("Expected an error once the retries ran out")
+	}
+	expect(t, atomic.LoadInt32(&attempts), int32(3))
+}
+
+func TestHttpBackendRetrySkipsClientErrors(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	hb := NewHttpClientBackendSync(server.URL, Retry(3, time.Millisecond))
+	buffer := logbuffer{Entries: testobj.Entries}
+	if err := hb.send(server.URL, &buffer); err == nil {
+		t.Fatal("Expected an error for a bad request")
+	}
+	expect(t, atomic.LoadInt32(&attempts), int32(1))
+}
+
+func TestHttpBackendOverflowNeverBlocks(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release // Stall until the test is over.
+	}))
+	defer server.Close()
+	defer close(release)
+
+	hb := NewHttpClientBackend(server.URL, 1, time.Hour)
+	defer close(hb.Stop)
+
+	done := make(chan struct{})
+	go func() {
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					entry := testobj.Entries[0]
+					hb.Log(&entry)
+				}
+			}()
+		}
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Callers blocked on a stalled backend")
+	}
+
+	if hb.Dropped() == 0 {
+		t.Error("Expected entries to be dropped by a stalled backend")
+	}
+}
+
+func TestHttpBackendOverflowPolicy(t *testing.T) {
+	tests := []struct {
+		policy OverflowPolicy
+		want   []string
+	}{
+		{DropNewest, []string{"one", "two"}},
+		{DropOldest, []string{"two", "three"}},
+	}
+
+	for _, test := range tests {
+		// No Goroutine is started, so the channel only fills up.
+		hb := &HttpClientBackend{
+			logchan:  make(chan LogEntry, 2),
+			done:     make(chan struct{}),
+			overflow: test.policy,
+		}
+
+		for _, message := range []string{"one", "two", "three"} {
+			entry := LogEntry{Level: INFO, Message: message}
+			hb.Log(&entry)
+		}
+
+		expect(t, hb.Dropped(), uint64(1))
+		expect(t, []string{(<-hb.logchan).Message, (<-hb.logchan).Message}, test.want)
+	}
+}
@@ -0,0 +1,149 @@
+package lumberjack
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+//MaxHTTPBodyBytes is the maximum number of bytes of a request or response
+//body captured by HTTPRequestFields and HTTPResponseFields.
+var MaxHTTPBodyBytes = 4096
+
+//MaxHTTPHeaderBytes is the maximum number of bytes of each header value
+//captured by HTTPRequestFields and HTTPResponseFields.
+var MaxHTTPHeaderBytes = 256
+
+//RedactedHTTPHeaders holds the names of the headers whose values are replaced
+//with RedactedValue by HTTPRequestFields and HTTPResponseFields.
+var RedactedHTTPHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Proxy-Authorization",
+	"Set-Cookie",
+	"X-Api-Key",
+}
+
+//RedactedValue is the value logged in place of a redacted header.
+const RedactedValue = "[REDACTED]"
+
+//HTTPRequestFields returns the method, URL, headers, and body of the specified
+//http.Request as structured fields suitable for WithFields, for debugging the
+//requests sent to or received from other services. Sensitive headers listed in
+//RedactedHTTPHeaders are redacted, and header values and the body are capped
+//at MaxHTTPHeaderBytes and MaxHTTPBodyBytes, with "body_truncated" set if the
+//body was longer.
+//
+//The body is read up to the cap and buffered, so that the request remains
+//readable from the start by whoever sends or handles it next. An error is
+//returned if the body can't be read.
+func HTTPRequestFields(req *http.Request) (map[string]interface{}, error) {
+	fields := map[string]interface{}{
+		"method":  req.Method,
+		"url":     req.URL.String(),
+		"headers": httpHeaderFields(req.Header),
+	}
+
+	body, err := peekHTTPBody(&req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read request body: %s", err)
+	}
+	setHTTPBodyFields(fields, body)
+
+	return fields, nil
+}
+
+//HTTPResponseFields returns the status, headers, and body of the specified
+//http.Response as structured fields suitable for WithFields, along with the
+//method and URL of the request it answers, if known. Headers and the body are
+//redacted and capped as with HTTPRequestFields, and the body remains readable
+//from the start afterwards. An error is returned if the body can't be read.
+func HTTPResponseFields(resp *http.Response) (map[string]interface{}, error) {
+	fields := map[string]interface{}{
+		"status":  resp.StatusCode,
+		"headers": httpHeaderFields(resp.Header),
+	}
+
+	if resp.Request != nil {
+		fields["method"] = resp.Request.Method
+		fields["url"] = resp.Request.URL.String()
+	}
+
+	body, err := peekHTTPBody(&resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read response body: %s", err)
+	}
+	setHTTPBodyFields(fields, body)
+
+	return fields, nil
+}
+
+//httpHeaderFields is an internal function that flattens the specified headers
+//into a map of header names to their comma separated values, redacting the
+//sensitive ones and truncating long values.
+func httpHeaderFields(header http.Header) map[string]string {
+	headers := make(map[string]string, len(header))
+	for name, values := range header {
+		if isRedactedHTTPHeader(name) {
+			headers[name] = RedactedValue
+			continue
+		}
+		value := strings.Join(values, ", ")
+		if MaxHTTPHeaderBytes > 0 && len(value) > MaxHTTPHeaderBytes {
+			value = value[:MaxHTTPHeaderBytes] + "..."
+		}
+		headers[name] = value
+	}
+	return headers
+}
+
+//isRedactedHTTPHeader is an internal function that checks if the specified
+//header is listed in RedactedHTTPHeaders.
+func isRedactedHTTPHeader(name string) bool {
+	for _, redacted := range RedactedHTTPHeaders {
+		if strings.EqualFold(name, redacted) {
+			return true
+		}
+	}
+	return false
+}
+
+//peekHTTPBody is an internal function that reads up to one byte more than
+//MaxHTTPBodyBytes from the specified body, then replaces it with a body that
+//yields the bytes read followed by the rest of the original body.
+func peekHTTPBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+
+	original := *body
+	peeked, err := ioutil.ReadAll(io.LimitReader(original, int64(MaxHTTPBodyBytes)+1))
+	*body = &replayBody{Reader: io.MultiReader(bytes.NewReader(peeked), original), Closer: original}
+	if err != nil {
+		return nil, err
+	}
+	return peeked, nil
+}
+
+//setHTTPBodyFields is an internal function that sets the captured body on the
+//specified fields, truncated to MaxHTTPBodyBytes.
+func setHTTPBodyFields(fields map[string]interface{}, body []byte) {
+	if body == nil {
+		return
+	}
+	if len(body) > MaxHTTPBodyBytes {
+		body = body[:MaxHTTPBodyBytes]
+		fields["body_truncated"] = true
+	}
+	fields["body"] = string(body)
+}
+
+//replayBody is a body that reads from the buffered start of the original body
+//followed by the rest of it, and closes the original body.
+type replayBody struct {
+	io.Reader
+	io.Closer
+}
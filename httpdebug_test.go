@@ -0,0 +1,86 @@
+package lumberjack
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTPRequestFields(t *testing.T) {
+	req := httptest.NewRequest("POST", "http://example.com/users?id=42", strings.NewReader(`{"name":"gopher"}`))
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("Content-Type", "application/json")
+
+	fields, err := HTTPRequestFields(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect(t, fields["method"], "POST")
+	expect(t, fields["url"], "http://example.com/users?id=42")
+	expect(t, fields["body"], `{"name":"gopher"}`)
+	_, truncated := fields["body_truncated"]
+	expect(t, truncated, false)
+
+	headers := fields["headers"].(map[string]string)
+	expect(t, headers["Authorization"], RedactedValue)
+	expect(t, headers["Content-Type"], "application/json")
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, string(body), `{"name":"gopher"}`)
+}
+
+func TestHTTPResponseFieldsTruncated(t *testing.T) {
+	defer func(max int) { MaxHTTPBodyBytes = max }(MaxHTTPBodyBytes)
+	MaxHTTPBodyBytes = 8
+
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Set-Cookie", "session=secret")
+	rec.WriteHeader(http.StatusBadGateway)
+	rec.WriteString("upstream timed out")
+
+	resp := rec.Result()
+	resp.Request = httptest.NewRequest("GET", "http://example.com/", nil)
+
+	fields, err := HTTPResponseFields(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect(t, fields["status"], http.StatusBadGateway)
+	expect(t, fields["method"], "GET")
+	expect(t, fields["body"], "upstream")
+	expect(t, fields["body_truncated"], true)
+	expect(t, fields["headers"].(map[string]string)["Set-Cookie"], RedactedValue)
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, string(body), "upstream timed out")
+}
+
+func TestHTTPRequestFieldsWithFields(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(DEBUG)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	fields, err := HTTPRequestFields(httptest.NewRequest("GET", "http://example.com/", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.WithFields(fields).Debug("outbound request")
+
+	entries := capture.Entries()
+	expect(t, len(entries), 1)
+	expect(t, entries[0].Fields["method"], "GET")
+	_, hasBody := entries[0].Fields["body"]
+	expect(t, hasBody, false)
+}
@@ -0,0 +1,57 @@
+package lumberjack
+
+import (
+	"io"
+	"os"
+	"sync"
+)
+
+//JSONBackend implements a Backend that writes each LogEntry as a single
+//JSON object followed by a newline to an io.Writer, producing newline
+//delimited JSON suitable for structured log collectors.
+//
+//If CompactCaller is set, the caller information of each LogEntry is reduced
+//to a single "src" field in the "file:line" form to reduce the output size.
+//
+//If FieldNames is set, the keys of each LogEntry are renamed with it, such as
+//with ECSFieldNames or CamelCaseFieldNames. If Formatter is set, it renders
+//each LogEntry instead, such as with the ECSFormatter.
+type JSONBackend struct {
+	CompactCaller bool
+	FieldNames    FieldNamer
+	Formatter     Formatter
+	writer        io.Writer
+	sync.Mutex
+}
+
+//NewJSONBackend returns an instance of JSONBackend that writes to the
+//specified io.Writer, or to os.Stdout if it is nil.
+func NewJSONBackend(w io.Writer) *JSONBackend {
+	if w == nil {
+		w = os.Stdout
+	}
+	return &JSONBackend{writer: w}
+}
+
+//Log satisfies the Backend interfaces requirements used for accepting
+//LogEntry objects to write out as JSON.
+func (b *JSONBackend) Log(entry *LogEntry) {
+	var data []byte
+	var err error
+	if b.Formatter != nil {
+		data, err = b.Formatter.Format(entry)
+	} else {
+		data, err = marshalEntryNamed(entry, b.CompactCaller, b.FieldNames)
+	}
+	if err != nil {
+		logInternalf(ERROR, "JSON Backend: unable to Marshal JSON from LogEntry: %s", err)
+		return
+	}
+
+	b.Lock()
+	defer b.Unlock()
+
+	if _, err := b.writer.Write(append(data, '\n')); err != nil {
+		logInternalf(ERROR, "JSON Backend: unable to write LogEntry: %s", err)
+	}
+}
@@ -0,0 +1,90 @@
+package lumberjack
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestJSONBackendCompactCaller(t *testing.T) {
+	var buf bytes.Buffer
+	backend := NewJSONBackend(&buf)
+	backend.CompactCaller = true
+
+	entry := testobj.Entries[0]
+	backend.Log(&entry)
+
+	out := map[string]interface{}{}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	expect(t, out["src"], "main.go:10")
+	expect(t, out["message"], "Test Error")
+	expect(t, out["level"], "ERROR")
+
+	for _, key := range []string{"caller", "path", "file", "line"} {
+		if _, exists := out[key]; exists {
+			t.Errorf("Expected key %q to be omitted in compact output", key)
+		}
+	}
+}
+
+func TestJSONBackendVerboseCaller(t *testing.T) {
+	var buf bytes.Buffer
+	backend := NewJSONBackend(&buf)
+
+	entry := testobj.Entries[0]
+	backend.Log(&entry)
+
+	out := LogEntry{}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	expect(t, out, entry)
+	expect(t, bytes.Contains(buf.Bytes(), []byte(`"src"`)), false)
+}
+
+func TestJSONBackendNilWriter(t *testing.T) {
+	expect(t, NewJSONBackend(nil).writer, os.Stdout)
+}
+
+func TestJSONBackendConcurrentLines(t *testing.T) {
+	var buf bytes.Buffer
+	backend := NewJSONBackend(&buf)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				entry := testobj.Entries[j%len(testobj.Entries)]
+				backend.Log(&entry)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Every line unmarshals back into one of the original entries.
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	expect(t, len(lines), 200)
+	for _, line := range lines {
+		var out LogEntry
+		if err := json.Unmarshal([]byte(line), &out); err != nil {
+			t.Fatal(err)
+		}
+		found := false
+		for _, entry := range testobj.Entries {
+			found = found || reflect.DeepEqual(out, entry)
+		}
+		if !found {
+			t.Errorf("Unexpected LogEntry: %s", line)
+		}
+	}
+}
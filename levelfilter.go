@@ -0,0 +1,43 @@
+package lumberjack
+
+import "io"
+
+//LevelFilterBackend implements a Backend that wraps another Backend and only
+//forwards the LogEntry objects at or above MinLevel to it, so that Backends
+//added to the same Logger can receive different LogLevels, such as DEBUG and
+//above to a FileBackend while only ERROR and above to an HttpClientBackend.
+type LevelFilterBackend struct {
+	Backend  Backend
+	MinLevel LogLevel
+}
+
+//NewLevelFilterBackend returns an instance of LevelFilterBackend that forwards
+//the LogEntry objects at or above the specified minimum LogLevel to the
+//specified Backend.
+func NewLevelFilterBackend(backend Backend, minLevel LogLevel) *LevelFilterBackend {
+	return &LevelFilterBackend{Backend: backend, MinLevel: minLevel}
+}
+
+//Log satisfies the Backend interfaces requirements used for accepting
+//LogEntry objects to filter by LogLevel.
+func (b *LevelFilterBackend) Log(entry *LogEntry) {
+	if entry.Level >= b.MinLevel {
+		b.Backend.Log(entry)
+	}
+}
+
+//Flush flushes the wrapped Backend if it implements the Flusher interface.
+func (b *LevelFilterBackend) Flush() error {
+	if f, ok := b.Backend.(Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+//Close closes the wrapped Backend if it implements io.Closer.
+func (b *LevelFilterBackend) Close() error {
+	if c, ok := b.Backend.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
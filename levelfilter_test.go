@@ -0,0 +1,25 @@
+package lumberjack
+
+import "testing"
+
+func TestLevelFilterBackend(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(DEBUG)
+	logger.AddLevel(INFO)
+	logger.AddLevel(ERROR)
+
+	all := &captureBackend{}
+	severe := &captureBackend{}
+	logger.AddBackend("all", all)
+	logger.AddBackend("severe", NewLevelFilterBackend(severe, WARN))
+
+	logger.Debug("debug")
+	logger.Info("info")
+	logger.Error("error")
+
+	expect(t, len(all.Entries()), 3)
+
+	entries := severe.Entries()
+	expect(t, len(entries), 1)
+	expect(t, entries[0].Message, "error")
+}
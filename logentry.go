@@ -1,12 +1,219 @@
 package lumberjack
 
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
 //LogEntry is the object used to contain the relevant
 //information for a particular log event.
 type LogEntry struct {
-	Level   LogLevel `json:"level"`
-	Caller  string   `json:"caller"`
-	Path    string   `json:"path"`
-	File    string   `json:"file"`
-	Line    int      `json:"line"`
-	Message string   `json:"message"`
+	Time    time.Time `json:"time"`
+	Level   LogLevel  `json:"level"`
+	Caller  string    `json:"caller"`
+	Path    string    `json:"path"`
+	File    string    `json:"file"`
+	Line    int       `json:"line"`
+	Message string    `json:"message"`
+	Version string    `json:"version,omitempty"`
+
+	Component   string `json:"component,omitempty"`
+	Transaction string `json:"transaction,omitempty"`
+	Stack       string `json:"stack,omitempty"`
+	Repeat      int    `json:"repeat,omitempty"`
+
+	Sampled    bool    `json:"sampled,omitempty"`
+	SampleRate float64 `json:"sample_rate,omitempty"`
+
+	Env        string `json:"env,omitempty"`
+	Region     string `json:"region,omitempty"`
+	InstanceID string `json:"instance_id,omitempty"`
+	Host       string `json:"host,omitempty"`
+	PID        int    `json:"pid,omitempty"`
+
+	Fields      map[string]interface{} `json:"fields,omitempty"`
+	Attachments map[string][]byte      `json:"attachments,omitempty"`
+
+	//Context is the context.Context of the Logger the LogEntry was sent
+	//with, if the Logger was derived with WithContext. It is not rendered.
+	Context context.Context `json:"-"`
+}
+
+//SetField sets a structured key/value field on the LogEntry, creating
+//the Fields map if needed.
+func (e *LogEntry) SetField(key string, value interface{}) {
+	if e.Fields == nil {
+		e.Fields = map[string]interface{}{}
+	}
+	e.Fields[key] = value
+}
+
+//ToMap returns a flat map of all of the fields of the LogEntry keyed by their
+//JSON names, with the LogLevel as a string and the structured Fields merged in
+//at the top level. If a structured field has the same key as a field of the
+//LogEntry, the field of the LogEntry takes precedence.
+func (e *LogEntry) ToMap() map[string]interface{} {
+	return e.ToMapPrefixed("")
+}
+
+//ToMapPrefixed returns a flat map of all of the fields of the LogEntry keyed
+//by their JSON names, with the LogLevel as a string and the keys of the
+//structured Fields prefixed with the specified prefix, such as "fields.".
+func (e *LogEntry) ToMapPrefixed(prefix string) map[string]interface{} {
+	m := map[string]interface{}{}
+
+	for key, value := range e.Fields {
+		m[prefix+key] = value
+	}
+
+	v := reflect.ValueOf(*e)
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		name, omitempty := jsonFieldName(t.Field(i))
+		field := v.Field(i)
+		if name == "" || field.Kind() == reflect.Map {
+			continue
+		}
+		if omitempty && field.IsZero() {
+			continue
+		}
+
+		value := field.Interface()
+		if level, ok := value.(LogLevel); ok {
+			value = level.String()
+		}
+		m[name] = value
+	}
+
+	return m
+}
+
+//Source returns the caller information of the LogEntry in the short
+//"file:line" form.
+func (e *LogEntry) Source() string {
+	return fmt.Sprintf("%s:%d", e.File, e.Line)
+}
+
+//compactLogEntry is a representation of a LogEntry used for Marshalling
+//into JSON with the caller information reduced to a single "file:line"
+//source field. The embedded LogEntry's caller fields are shadowed by empty
+//fields which are then omitted.
+type compactLogEntry struct {
+	*LogEntry
+	Caller string `json:"caller,omitempty"`
+	Path   string `json:"path,omitempty"`
+	File   string `json:"file,omitempty"`
+	Line   int    `json:"line,omitempty"`
+	Source string `json:"src"`
+}
+
+//entryPayload returns the object to be Marshalled into JSON for a LogEntry,
+//with the caller information reduced to a compact source field if compact
+//is true.
+func entryPayload(entry *LogEntry, compact bool) interface{} {
+	if compact {
+		return compactLogEntry{LogEntry: entry, Source: entry.Source()}
+	}
+	return entry
+}
+
+//marshalEntry Marshals a LogEntry into JSON, with the caller information
+//reduced to a compact source field if compact is true. If the LogEntry fails
+//to Marshal, a sanitized representation is Marshalled instead.
+func marshalEntry(entry *LogEntry, compact bool) ([]byte, error) {
+	data, err := json.Marshal(entryPayload(entry, compact))
+	if err == nil {
+		return data, nil
+	}
+
+	sanitized := sanitizeEntry(*entry)
+	if compact {
+		for _, key := range []string{"caller", "path", "file", "line"} {
+			delete(sanitized, key)
+		}
+		sanitized["src"] = entry.Source()
+	}
+
+	return json.Marshal(sanitized)
+}
+
+//sanitizeEntry builds a representation of a LogEntry that is safe to be
+//Marshalled into JSON. Each field of the LogEntry is Marshalled on its own,
+//and any field that fails is replaced with a string placeholder describing
+//the error. Fields that are maps are sanitized per key so that a single bad
+//value does not hide the rest of the map.
+func sanitizeEntry(entry LogEntry) map[string]interface{} {
+	sanitized := map[string]interface{}{}
+
+	v := reflect.ValueOf(entry)
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		name, omitempty := jsonFieldName(t.Field(i))
+		if name == "" {
+			continue
+		}
+
+		field := v.Field(i)
+		if omitempty && field.IsZero() {
+			continue
+		}
+
+		if field.Kind() == reflect.Map && field.Type().Key().Kind() == reflect.String {
+			values := map[string]interface{}{}
+			iter := field.MapRange()
+			for iter.Next() {
+				values[iter.Key().String()] = sanitizeValue(iter.Value().Interface())
+			}
+			sanitized[name] = values
+			continue
+		}
+
+		sanitized[name] = sanitizeValue(field.Interface())
+	}
+
+	return sanitized
+}
+
+//sanitizeValue returns the specified value if it can be Marshalled into
+//JSON, otherwise a string placeholder describing the Marshal error.
+func sanitizeValue(value interface{}) interface{} {
+	if _, err := json.Marshal(value); err != nil {
+		return fmt.Sprintf("!MARSHAL_ERROR(%s)", err)
+	}
+	return value
+}
+
+//jsonFieldName returns the JSON key name of a struct field based on its json
+//struct tag, and whether the field is tagged omitempty. An empty name is
+//returned for fields that are not Marshalled.
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	if field.PkgPath != "" {
+		return "", false
+	}
+
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+
+	parts := strings.Split(tag, ",")
+	name := parts[0]
+	if name == "" {
+		name = field.Name
+	}
+
+	omitempty := false
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitempty = true
+		}
+	}
+
+	return name, omitempty
 }
@@ -0,0 +1,78 @@
+package lumberjack
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestLogEntryToMap(t *testing.T) {
+	entry := testobj.Entries[0]
+	entry.Component = "payments"
+	entry.SetField("user", 42)
+	entry.SetField("message", "shadowed")
+
+	m := entry.ToMap()
+
+	expect(t, m["level"], "ERROR")
+	expect(t, m["caller"], "main.main()")
+	expect(t, m["path"], "/somewhere")
+	expect(t, m["file"], "main.go")
+	expect(t, m["line"], 10)
+	expect(t, m["message"], "Test Error")
+	expect(t, m["component"], "payments")
+	expect(t, m["user"], 42)
+
+	// Empty omitempty fields are left out.
+	_, exists := m["stack"]
+	expect(t, exists, false)
+	_, exists = m["fields"]
+	expect(t, exists, false)
+}
+
+func TestLogEntryToMapPrefixed(t *testing.T) {
+	entry := testobj.Entries[1]
+	entry.SetField("user", 42)
+	entry.SetField("message", "not shadowed")
+
+	m := entry.ToMapPrefixed("fields.")
+
+	expect(t, m["level"], "INFO")
+	expect(t, m["message"], "Test Info")
+	expect(t, m["fields.user"], 42)
+	expect(t, m["fields.message"], "not shadowed")
+
+	_, exists := m["user"]
+	expect(t, exists, false)
+}
+
+func TestLogEntryTime(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	before := time.Now()
+	logger.Info("timed")
+	after := time.Now()
+
+	entry := capture.Entries()[0]
+	expect(t, entry.Time.Before(before), false)
+	expect(t, entry.Time.After(after), false)
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := LogEntry{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, out.Time.Equal(entry.Time), true)
+
+	raw := map[string]interface{}{}
+	json.Unmarshal(data, &raw)
+	expect(t, raw["time"], entry.Time.Format(time.RFC3339Nano))
+}
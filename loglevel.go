@@ -3,18 +3,21 @@ package lumberjack
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 )
 
 type LogLevel byte
 
-//Constants used to define the various LogLevels
+//Constants used to define the various LogLevels, in order of increasing
+//severity, so that LogLevels can be compared directly.
 const (
-	INFO LogLevel = iota
+	TRACE LogLevel = iota
+	DEBUG
+	INFO
 	WARN
 	ERROR
 	CRITICAL
 	FATAL
-	DEBUG
 )
 
 //logLevelNameToValue is a map that will allow for conversion
@@ -26,6 +29,7 @@ var logLevelNameToValue = map[string]LogLevel{
 	"CRITICAL": CRITICAL,
 	"FATAL":    FATAL,
 	"DEBUG":    DEBUG,
+	"TRACE":    TRACE,
 }
 
 //logLevelNameToValue is a map that will allow for conversion
@@ -37,6 +41,7 @@ var logLevelValueToName = map[LogLevel]string{
 	CRITICAL: "CRITICAL",
 	FATAL:    "FATAL",
 	DEBUG:    "DEBUG",
+	TRACE:    "TRACE",
 }
 
 //String satisfies fmt.Stringer interface fo use in Marshalling
@@ -48,6 +53,39 @@ func (l LogLevel) String() string {
 	return ""
 }
 
+//LevelCase is used by the text backends to choose the letter case a
+//LogLevel is rendered in.
+type LevelCase byte
+
+//Constants used to define the LevelCases, where the default is LevelUpper.
+const (
+	LevelUpper LevelCase = iota
+	LevelLower
+)
+
+//LevelStyle is used by the text backends to choose whether a LogLevel is
+//rendered as its full name or as a single letter abbreviation.
+type LevelStyle byte
+
+//Constants used to define the LevelStyles, where the default is LevelFull.
+const (
+	LevelFull LevelStyle = iota
+	LevelShort
+)
+
+//format returns the name of the LogLevel rendered in the specified LevelCase
+//and LevelStyle, such as "INFO", "info", "I", or "i".
+func (l LogLevel) format(levelCase LevelCase, style LevelStyle) string {
+	name := l.String()
+	if style == LevelShort && name != "" {
+		name = name[:1]
+	}
+	if levelCase == LevelLower {
+		name = strings.ToLower(name)
+	}
+	return name
+}
+
 // MarshalJSON satisfies json.Marshaler.
 func (l LogLevel) MarshalJSON() ([]byte, error) {
 	s, ok := logLevelValueToName[l]
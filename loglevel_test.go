@@ -0,0 +1,71 @@
+package lumberjack
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTraceLevel(t *testing.T) {
+	expect(t, TRACE.String(), "TRACE")
+	expect(t, logLevelNameToValue["TRACE"], TRACE)
+	expect(t, validLevel(TRACE), true)
+
+	data, err := json.Marshal(TRACE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, string(data), `"TRACE"`)
+
+	var level LogLevel
+	if err := json.Unmarshal(data, &level); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, level, TRACE)
+
+	// TRACE is less severe than DEBUG, which is less severe than INFO.
+	expect(t, TRACE < DEBUG, true)
+	expect(t, DEBUG < INFO, true)
+}
+
+func TestTrace(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(DEBUG)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	logger.Trace("hidden")
+	expect(t, len(capture.Entries()), 0)
+
+	logger.AddLevel(TRACE)
+	logger.Tracef("shown %d", 1)
+	logger.TraceAt(CaptureCaller(), "at")
+
+	entries := capture.Entries()
+	expect(t, len(entries), 2)
+	expect(t, entries[0].Level, TRACE)
+	expect(t, entries[0].Message, "shown 1")
+	expect(t, entries[1].File, "loglevel_test.go")
+}
+
+func TestValidLevel(t *testing.T) {
+	for level := range logLevelValueToName {
+		expect(t, validLevel(level), true)
+	}
+	expect(t, validLevel(LogLevel(7)), false)
+	expect(t, validLevel(LogLevel(200)), false)
+}
+
+func TestLevelOrder(t *testing.T) {
+	order := []LogLevel{TRACE, DEBUG, INFO, WARN, ERROR, CRITICAL, FATAL}
+	for i := 1; i < len(order); i++ {
+		expect(t, order[i-1] < order[i], true)
+	}
+
+	// DEBUG is below the ERROR verbosity, so it's printed without the source.
+	entry := testobj.Entries[1]
+	entry.Level = DEBUG
+	expect(t, formatEntry(ERROR, "DEBUG", &entry), "(DEBUG) @ main.main()(): Test Info")
+	entry.Level = FATAL
+	expect(t, formatEntry(ERROR, "FATAL", &entry), "(FATAL) @ main.main()() main.go:11: Test Info")
+}
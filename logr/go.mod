@@ -0,0 +1,12 @@
+module github.com/btnmasher/lumberjack/logr
+
+go 1.18
+
+require (
+	github.com/btnmasher/lumberjack v0.0.0-20261016010807-99c87475ee33
+	github.com/go-logr/logr v1.4.2
+)
+
+//Builds within this repository use the root module alongside it, while
+//dependents resolve the version required above.
+replace github.com/btnmasher/lumberjack => ../
@@ -0,0 +1,137 @@
+//Package logr adapts a lumberjack Logger to the go-logr LogSink interface,
+//so that lumberjack can serve as the backend of any library logging with
+//github.com/go-logr/logr, such as those in the Kubernetes ecosystem. It is
+//kept in its own module so that the lumberjack package stays free of the
+//dependency for users who don't need it.
+package logr
+
+import (
+	"fmt"
+
+	"github.com/btnmasher/lumberjack"
+	gologr "github.com/go-logr/logr"
+)
+
+//ErrorField is the key of the field holding the message of the error logged
+//with the Error method of a logr.Logger.
+const ErrorField = "error"
+
+//sink implements the logr.LogSink interface by sending each log line to a
+//lumberjack Logger.
+type sink struct {
+	logger *lumberjack.Logger
+	name   string
+	depth  int
+}
+
+//NewLogrSink returns a logr.LogSink that sends each log line to the specified
+//Logger, to be passed to logr.New. The verbosity levels of logr are mapped to
+//LogLevels, V(0) being INFO, V(1) being DEBUG, and V(2) and above being TRACE,
+//while errors are logged at ERROR with the message of the error in the
+//"error" field. The key and value pairs are set as the Fields of each
+//LogEntry, and the names given with WithName are joined with "/" to be the
+//component of each LogEntry.
+func NewLogrSink(l *lumberjack.Logger) gologr.LogSink {
+	return &sink{logger: l}
+}
+
+//levelFor returns the LogLevel the specified logr verbosity level maps to.
+func levelFor(level int) lumberjack.LogLevel {
+	switch {
+	case level <= 0:
+		return lumberjack.INFO
+	case level == 1:
+		return lumberjack.DEBUG
+	default:
+		return lumberjack.TRACE
+	}
+}
+
+//Init satisfies the logr.LogSink interface, recording the number of frames
+//between the logr.Logger and its caller.
+func (s *sink) Init(info gologr.RuntimeInfo) {
+	s.depth = info.CallDepth
+}
+
+//Enabled satisfies the logr.LogSink interface, reporting whether the LogLevel
+//the specified verbosity level maps to is logged by the Logger.
+func (s *sink) Enabled(level int) bool {
+	return s.logger.Enabled(levelFor(level))
+}
+
+//Info satisfies the logr.LogSink interface, logging the specified message
+//with the specified key and value pairs at the LogLevel the specified
+//verbosity level maps to.
+func (s *sink) Info(level int, msg string, keysAndValues ...interface{}) {
+	cs := lumberjack.CaptureCallerSkip(s.depth + 1)
+	logger := s.logger.WithFields(kvFields(keysAndValues))
+
+	switch levelFor(level) {
+	case lumberjack.INFO:
+		logger.InfoAt(cs, msg)
+	case lumberjack.DEBUG:
+		logger.DebugAt(cs, msg)
+	default:
+		logger.TraceAt(cs, msg)
+	}
+}
+
+//Error satisfies the logr.LogSink interface, logging the specified message
+//and error with the specified key and value pairs at ERROR.
+func (s *sink) Error(err error, msg string, keysAndValues ...interface{}) {
+	cs := lumberjack.CaptureCallerSkip(s.depth + 1)
+	fields := kvFields(keysAndValues)
+	if err != nil {
+		fields[ErrorField] = err.Error()
+	}
+	s.logger.WithFields(fields).ErrorAt(cs, msg)
+}
+
+//WithValues satisfies the logr.LogSink interface, returning a logr.LogSink
+//that sets the specified key and value pairs on every LogEntry.
+func (s *sink) WithValues(keysAndValues ...interface{}) gologr.LogSink {
+	child := *s
+	child.logger = s.logger.WithFields(kvFields(keysAndValues))
+	return &child
+}
+
+//WithName satisfies the logr.LogSink interface, returning a logr.LogSink
+//that appends the specified name to the component of every LogEntry.
+func (s *sink) WithName(name string) gologr.LogSink {
+	child := *s
+	if s.name != "" {
+		name = s.name + "/" + name
+	}
+	child.name = name
+	child.logger = s.logger.WithComponent(name)
+	return &child
+}
+
+//WithCallDepth satisfies the logr.CallDepthLogSink interface, returning a
+//logr.LogSink that skips the specified number of additional frames when
+//recording the caller of each LogEntry.
+func (s *sink) WithCallDepth(depth int) gologr.LogSink {
+	child := *s
+	child.depth += depth
+	return &child
+}
+
+//kvFields converts alternating key and value arguments into a map of fields.
+//Keys that are not strings are formatted as strings, and a key without a
+//value is given a placeholder value, as lumberjack does.
+func kvFields(keysAndValues []interface{}) map[string]interface{} {
+	fields := map[string]interface{}{}
+	for i := 0; i < len(keysAndValues); i += 2 {
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = fmt.Sprint(keysAndValues[i])
+		}
+
+		if i+1 < len(keysAndValues) {
+			fields[key] = keysAndValues[i+1]
+		} else {
+			fields[key] = "!MISSING_VALUE"
+		}
+	}
+	return fields
+}
@@ -0,0 +1,99 @@
+package logr
+
+import (
+	"errors"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/btnmasher/lumberjack"
+	gologr "github.com/go-logr/logr"
+)
+
+func expect(t *testing.T, a interface{}, b interface{}) {
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("Expected %v (type %v) - Got %v (type %v)", b, reflect.TypeOf(b), a, reflect.TypeOf(a))
+	}
+}
+
+//newTestLogger returns a logr.Logger over a lumberjack Logger with every
+//LogLevel added, along with a channel receiving each LogEntry it sends.
+func newTestLogger(t *testing.T) (gologr.Logger, <-chan lumberjack.LogEntry) {
+	logger := lumberjack.NewLogger()
+	for _, level := range []lumberjack.LogLevel{lumberjack.TRACE, lumberjack.DEBUG, lumberjack.INFO, lumberjack.ERROR} {
+		logger.AddLevel(level)
+	}
+
+	entries, unsubscribe := logger.Subscribe(10)
+	t.Cleanup(unsubscribe)
+
+	return gologr.New(NewLogrSink(logger)), entries
+}
+
+func TestLogrSinkLevels(t *testing.T) {
+	log, entries := newTestLogger(t)
+
+	log.Info("info")
+	log.V(1).Info("debug")
+	log.V(2).Info("trace")
+	log.V(5).Info("trace too")
+
+	expect(t, (<-entries).Level, lumberjack.INFO)
+	expect(t, (<-entries).Level, lumberjack.DEBUG)
+	expect(t, (<-entries).Level, lumberjack.TRACE)
+	expect(t, (<-entries).Level, lumberjack.TRACE)
+}
+
+func TestLogrSinkEnabled(t *testing.T) {
+	logger := lumberjack.NewLogger()
+	logger.AddLevel(lumberjack.INFO)
+	logger.AddBackend("discard", lumberjack.DiscardBackend{})
+
+	log := gologr.New(NewLogrSink(logger))
+	expect(t, log.Enabled(), true)
+	expect(t, log.V(1).Enabled(), false)
+}
+
+func TestLogrSinkKeysAndValues(t *testing.T) {
+	log, entries := newTestLogger(t)
+
+	log.WithValues("request", "abc").Info("handled", "status", 200, 7, "seven", "dangling")
+
+	_, _, line, _ := runtime.Caller(0)
+	entry := <-entries
+	expect(t, entry.Message, "handled")
+	expect(t, entry.Fields, map[string]interface{}{
+		"request":  "abc",
+		"status":   200,
+		"7":        "seven",
+		"dangling": "!MISSING_VALUE",
+	})
+
+	//The caller is the code calling the logr.Logger, not the adapter.
+	expect(t, entry.File, "logr_test.go")
+	expect(t, entry.Line, line-2)
+}
+
+func TestLogrSinkError(t *testing.T) {
+	log, entries := newTestLogger(t)
+
+	log.Error(errors.New("boom"), "failed", "attempt", 2)
+	log.Error(nil, "failed without an error")
+
+	entry := <-entries
+	expect(t, entry.Level, lumberjack.ERROR)
+	expect(t, entry.Message, "failed")
+	expect(t, entry.Fields, map[string]interface{}{"attempt": 2, ErrorField: "boom"})
+
+	entry = <-entries
+	expect(t, entry.Level, lumberjack.ERROR)
+	expect(t, len(entry.Fields), 0)
+}
+
+func TestLogrSinkWithName(t *testing.T) {
+	log, entries := newTestLogger(t)
+
+	log.WithName("controller").WithName("pods").Info("reconciled")
+
+	expect(t, (<-entries).Component, "controller/pods")
+}
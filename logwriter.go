@@ -0,0 +1,41 @@
+package lumberjack
+
+import (
+	"io"
+	"strings"
+)
+
+//levelWriter is an io.Writer that logs what is written to it at a LogLevel.
+type levelWriter struct {
+	logger *Logger
+	level  LogLevel
+}
+
+//Writer returns an io.Writer that logs everything written to it at the
+//specified LogLevel to all added Backend objects added to the current Logger,
+//if the LogLevel is currently added to the Logger. Each line of a write is
+//logged as a separate LogEntry, with blank lines left out.
+//This allows for the output of libraries that only accept an io.Writer to be
+//funneled through the Logger, such as with the SetOutput function of the
+//standard library log package.
+func (l *Logger) Writer(level LogLevel) io.Writer {
+	return &levelWriter{logger: l, level: level}
+}
+
+//Write satisfies the io.Writer interface, logging each line of the specified
+//data. It always reports the whole of the data as written.
+func (w *levelWriter) Write(data []byte) (int, error) {
+	skip, ok := w.logger.begin(w.level)
+	if !ok {
+		return len(data), nil
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if line == "" {
+			continue
+		}
+		w.logger.log(w.level, skip, line)
+	}
+	return len(data), nil
+}
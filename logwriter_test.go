@@ -0,0 +1,48 @@
+package lumberjack
+
+import (
+	"fmt"
+	"log"
+	"testing"
+)
+
+func TestWriter(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(WARN)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	w := logger.Writer(WARN)
+	n, err := fmt.Fprint(w, "first line\nsecond line\r\n\nthird line\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, n, 36)
+
+	entries := capture.Entries()
+	expect(t, len(entries), 3)
+	expect(t, entries[0].Level, WARN)
+	expect(t, entries[0].Message, "first line")
+	expect(t, entries[1].Message, "second line")
+	expect(t, entries[2].Message, "third line")
+
+	// Nothing is logged at a LogLevel that isn't added.
+	fmt.Fprintln(logger.Writer(DEBUG), "hidden")
+	expect(t, len(capture.Entries()), 3)
+}
+
+func TestWriterStandardLog(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	std := log.New(logger.Writer(INFO), "lib: ", 0)
+	std.Println("connected")
+
+	entries := capture.Entries()
+	expect(t, len(entries), 1)
+	expect(t, entries[0].Message, "lib: connected")
+}
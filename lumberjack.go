@@ -4,21 +4,75 @@
 package lumberjack
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"math/rand"
 	"os"
-	"path/filepath"
-	"runtime"
+	"sort"
+	"strings"
 	"sync"
 )
 
 //Logger holds the configuration for LogLevel state and references to in-use backends.
+//
+//Child Loggers derived from a Logger, such as with WithComponent or
+//WithTransaction, share the configuration of the Logger they were derived
+//from.
 type Logger struct {
-	logLevels map[LogLevel]struct{}
-	backends  map[string]Backend
+	*loggerState
+	component   string
+	transaction string
+	ctx         context.Context
+	fields      map[string]interface{}
+	attachments map[string][]byte
+}
+
+//loggerState holds the configuration shared between a Logger and all
+//of the child Loggers derived from it.
+type loggerState struct {
+	logLevels       map[LogLevel]struct{}
+	levelsShared    bool
+	backends        map[string]Backend
+	sizeStats       *SizeStats
+	enrichers       map[LogLevel][]func(*LogEntry)
+	version         string
+	componentLevels map[string]LogLevel
+	coalescer       *coalescer
+	deployment      deploymentInfo
+	hostPID         *hostPID
+	counts          map[LogLevel]uint64
+	loggedBytes     uint64
+	closeSummary    bool
+	closed          bool
+	alertGuard      *alertGuard
+	disabled        map[string]struct{}
+	subscriptions   uint64
+	timerLevel      LogLevel
+	printLevel      LogLevel
+	onFatal         func(code int)
+	baggageKeys     []string
+	baggageLookup   BaggageLookupFunc
+	bootstrap       *bootstrapBuffer
+	clock           Clock
+	warnedNoBackend bool
+	quietNoBackend  bool
+	fieldPolicy     FieldPolicy
+	routes          []route
+	middlewares     []Middleware
+	sampleRate      float64
+	sampler         *counterSampler
+	sampleThreshold LogLevel
+	rateLimits      map[LogLevel]*rateLimiter
+	inflight        sync.RWMutex
+	rng             *rand.Rand
+	rawCallers      bool
+	callerSkip      int
 	sync.Mutex
 }
 
-//defaultLevels contains sensible defaults for most regular logging needs.
+//defaultLevels contains sensible defaults for most regular logging needs,
+//being INFO and every more severe LogLevel, leaving out DEBUG and TRACE.
 var defaultLevels map[LogLevel]struct{} = map[LogLevel]struct{}{
 	INFO:     {},
 	WARN:     {},
@@ -27,9 +81,28 @@ var defaultLevels map[LogLevel]struct{} = map[LogLevel]struct{}{
 	FATAL:    {},
 }
 
+//DefaultLevels returns the LogLevels added to a Logger created with
+//NewLoggerWithDefaults in order of increasing severity, being INFO, WARN,
+//ERROR, CRITICAL, and FATAL. DEBUG and TRACE are left out, and can be added
+//with AddLevel or SetMinLevel.
+func DefaultLevels() []LogLevel {
+	levels := make([]LogLevel, 0, len(defaultLevels))
+	for level := range defaultLevels {
+		levels = append(levels, level)
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i] < levels[j] })
+	return levels
+}
+
+//newLoggerState returns an instance of loggerState with the Timer and Print
+//methods logging at INFO.
+func newLoggerState() *loggerState {
+	return &loggerState{timerLevel: INFO, printLevel: INFO, sampleThreshold: WARN}
+}
+
 //NewLogger returns an empty instance of Logger.
 func NewLogger() *Logger {
-	logger := Logger{}
+	logger := Logger{loggerState: newLoggerState()}
 	logger.logLevels = map[LogLevel]struct{}{}
 	logger.backends = map[string]Backend{}
 	return &logger
@@ -37,10 +110,11 @@ func NewLogger() *Logger {
 
 //NewLoggerWithDefaults returns an instance of Logger with sensible defaults and a print backend.
 func NewLoggerWithDefaults() *Logger {
-	logger := Logger{}
+	logger := Logger{loggerState: newLoggerState()}
 
-	//Start withdefault log levels (all minus DEBUG)
-	logger.logLevels = copyMap(defaultLevels)
+	//Start with the default log levels (INFO and above), only copied once changed
+	logger.logLevels = defaultLevels
+	logger.levelsShared = true
 
 	//Start with default print logger
 	logger.backends = map[string]Backend{"print": &PrintBackend{Verbosity: ERROR}}
@@ -48,6 +122,55 @@ func NewLoggerWithDefaults() *Logger {
 	return &logger
 }
 
+//NewStructuredLogger returns an instance of Logger with all LogLevels added
+//and a JSON backend writing newline delimited JSON to os.Stdout, suitable
+//for structured logging in containerized deployments.
+func NewStructuredLogger() *Logger {
+	return newStructuredLogger(os.Stdout)
+}
+
+//newStructuredLogger returns an instance of Logger with all LogLevels added
+//and a JSON backend writing to the specified io.Writer.
+func newStructuredLogger(w io.Writer) *Logger {
+	logger := NewLogger()
+
+	for level := range logLevelValueToName {
+		logger.logLevels[level] = struct{}{}
+	}
+
+	logger.backends["json"] = NewJSONBackend(w)
+
+	return logger
+}
+
+//NewLoggerFromLevels returns an instance of Logger with no backends that
+//starts with the LogLevels added to the specified parent Logger. The LogLevels
+//are shared with the parent until either Logger adds or removes a LogLevel,
+//at which point that Logger gets its own copy, so creating many short-lived
+//Loggers, such as one per request, is cheap while each remains isolated.
+func NewLoggerFromLevels(parent *Logger) *Logger {
+	parent.Lock()
+	parent.levelsShared = true
+	levels := parent.logLevels
+	parent.Unlock()
+
+	logger := Logger{loggerState: newLoggerState()}
+	logger.logLevels = levels
+	logger.levelsShared = true
+	logger.backends = map[string]Backend{}
+	return &logger
+}
+
+//ownLevels makes sure the LogLevels of the current Logger are not shared
+//with any other Logger before they are changed, copying them if they are.
+//The caller must hold the lock.
+func (l *Logger) ownLevels() {
+	if l.levelsShared {
+		l.logLevels = copyMap(l.logLevels)
+		l.levelsShared = false
+	}
+}
+
 //copyMap makes a non-reference copy of a map of LogLevel keys.
 func copyMap(original map[LogLevel]struct{}) map[LogLevel]struct{} {
 	newmap := map[LogLevel]struct{}{}
@@ -61,6 +184,7 @@ func copyMap(original map[LogLevel]struct{}) map[LogLevel]struct{} {
 func (l *Logger) AddLevel(level LogLevel) error {
 	if !l.levelSet(level) && validLevel(level) {
 		l.Lock()
+		l.ownLevels()
 		l.logLevels[level] = struct{}{}
 		l.Unlock()
 	} else {
@@ -73,6 +197,7 @@ func (l *Logger) AddLevel(level LogLevel) error {
 func (l *Logger) RemoveLevel(level LogLevel) error {
 	if l.levelSet(level) {
 		l.Lock()
+		l.ownLevels()
 		delete(l.logLevels, level)
 		l.Unlock()
 	} else {
@@ -81,46 +206,276 @@ func (l *Logger) RemoveLevel(level LogLevel) error {
 	return nil
 }
 
+//SetMinLevel replaces the LogLevels added to the current Logger with the
+//specified LogLevel and every more severe LogLevel, such as WARN, ERROR,
+//CRITICAL, and FATAL for WARN.
+func (l *Logger) SetMinLevel(level LogLevel) error {
+	if !validLevel(level) {
+		return fmt.Errorf("Invalid LogLevel: %d", level)
+	}
+
+	levels := map[LogLevel]struct{}{}
+	for added := range logLevelValueToName {
+		if added >= level {
+			levels[added] = struct{}{}
+		}
+	}
+
+	l.Lock()
+	defer l.Unlock()
+	l.logLevels = levels
+	l.levelsShared = false
+	return nil
+}
+
+//MinLevel returns the least severe LogLevel added to the current Logger. If
+//no LogLevels are added, FATAL is returned, though nothing is logged.
+func (l *Logger) MinLevel() LogLevel {
+	l.Lock()
+	defer l.Unlock()
+
+	min := FATAL
+	for level := range l.logLevels {
+		if level < min {
+			min = level
+		}
+	}
+	return min
+}
+
 //AddBackend adds an object implementing the Backend interface to the current Logger.
 //A name must be specified to add the Backend to the collection as to differentiate
 //it from other Backends. This allows multiple instances of the same Backend object
-//to be added to the collection with different configurations.
+//to be added to the collection with different configurations. The name must not be
+//empty, have leading or trailing whitespace, or start with the reserved "__" prefix.
 func (l *Logger) AddBackend(name string, backend Backend) error {
-	if !l.backendAdded(name) {
-		l.Lock()
-		l.backends[name] = backend
+	if err := validBackendName(name); err != nil {
+		return err
+	}
+	return l.addBackend(name, backend)
+}
+
+//addBackend adds the specified Backend to the current Logger with the
+//specified name without validating it, so that Backends added internally can
+//use the reserved prefix, then replays any bootstrap buffer to it.
+func (l *Logger) addBackend(name string, backend Backend) error {
+	l.Lock()
+	if _, exists := l.backends[name]; exists {
 		l.Unlock()
-	} else {
 		return fmt.Errorf("Backend with that name already exists: %s", name)
 	}
+	l.backends[name] = backend
+	buffer := l.takeBootstrap()
+	l.Unlock()
+
+	l.replayBootstrap(buffer)
+	return nil
+}
+
+//reservedBackendPrefix is the prefix of Backend names reserved for
+//Backends added internally by the lumberjack package.
+const reservedBackendPrefix = "__"
+
+//validBackendName checks the specified name of a Backend to be added to
+//a Logger, returning an error describing why the name is invalid if it is
+//empty, has leading or trailing whitespace, or uses the reserved prefix.
+func validBackendName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("Backend name must not be empty")
+	}
+	if strings.TrimSpace(name) != name {
+		return fmt.Errorf("Backend name must not have leading or trailing whitespace: %q", name)
+	}
+	if strings.HasPrefix(name, reservedBackendPrefix) {
+		return fmt.Errorf("Backend name prefix %q is reserved for internal use: %s", reservedBackendPrefix, name)
+	}
 	return nil
 }
 
-//GetBackend returns a reference to an object implementing the Backend
-//interface associated with the current Logger. The reference is retrieved
-//from a map collection with the name of the Backend as the key that was
-//Specified when adding the Backend to the collection.
-func (l *Logger) GetBackend(name string) (*Backend, error) {
+//AddBackends adds a collection of objects implementing the Backend interface
+//to the current Logger, keyed by the names they should be added with. All
+//names are validated before any Backend is added, so if any of the names
+//collide with a Backend already added, none of the collection is added and
+//the returned error lists every colliding name.
+func (l *Logger) AddBackends(backends map[string]Backend) error {
+	l.Lock()
+
+	var collisions []string
+	for name := range backends {
+		if err := validBackendName(name); err != nil {
+			l.Unlock()
+			return err
+		}
+		if _, exists := l.backends[name]; exists {
+			collisions = append(collisions, name)
+		}
+	}
+
+	if len(collisions) > 0 {
+		l.Unlock()
+		sort.Strings(collisions)
+		return fmt.Errorf("Backends with those names already exist: %s", strings.Join(collisions, ", "))
+	}
+
+	for name, backend := range backends {
+		l.backends[name] = backend
+	}
+	buffer := l.takeBootstrap()
+	l.Unlock()
+
+	l.replayBootstrap(buffer)
+	return nil
+}
+
+//GetBackend returns the object implementing the Backend interface added to
+//the current Logger with the specified name, which can be type asserted to
+//its concrete type, such as *PrintBackend, to change its configuration.
+func (l *Logger) GetBackend(name string) (Backend, error) {
 	l.Lock()
 	defer l.Unlock()
 	if b, exists := l.backends[name]; exists {
-		return &b, nil
+		if check, ok := b.(*firstWriteCheck); ok {
+			return check.backend, nil
+		}
+		return b, nil
 	}
 	return nil, fmt.Errorf("Backend with that name does not exist: %s", name)
+}
 
+//RemoveBackend removes the object implementing the Backend interface added
+//to the current Logger with the specified name. An error is returned if no
+//Backend with that name has been added.
+func (l *Logger) RemoveBackend(name string) error {
+	l.Lock()
+	defer l.Unlock()
+	if _, exists := l.backends[name]; !exists {
+		return fmt.Errorf("Backend with that name does not exist: %s", name)
+	}
+	delete(l.backends, name)
+	delete(l.disabled, name)
+	return nil
 }
 
-//RemoveBackend removes a specified object implementing the Backend interface
-//from the current Logger. The name is used to specify which reference to be
-//removed from the collection.
-func (l *Logger) RemoveBackend(name string, backend Backend) error {
-	if l.backendAdded(name) {
-		l.Lock()
-		delete(l.backends, name)
-		l.Unlock()
-	} else {
+//ReplaceBackendDrained replaces the Backend added to the current Logger with
+//the specified name by the specified Backend. The old Backend is flushed if it
+//implements the Flusher interface and closed if it implements io.Closer
+//before any LogEntry is sent to the new Backend. No LogEntry is sent to any
+//Backend during the handoff, so every LogEntry is sent to exactly one of
+//either the old or new Backend. If the old Backend fails to flush, it is put
+//back in place of the new Backend, unless that was removed meanwhile, and the
+//error is returned.
+func (l *Logger) ReplaceBackendDrained(name string, backend Backend) error {
+	l.inflight.Lock() //Wait for any LogEntry being sent to the old Backend.
+	defer l.inflight.Unlock()
+
+	l.Lock()
+	old, exists := l.backends[name]
+	if exists {
+		l.backends[name] = backend
+	}
+	l.Unlock()
+
+	if !exists {
+		return fmt.Errorf("Backend with that name does not exist: %s", name)
+	}
+
+	//Flushing and closing may block on I/O, so they are done without holding
+	//the lock. Holding inflight still keeps any LogEntry from being sent.
+	if f, ok := old.(Flusher); ok {
+		if err := f.Flush(); err != nil {
+			l.Lock()
+			if _, exists := l.backends[name]; exists {
+				l.backends[name] = old
+			}
+			l.Unlock()
+			return fmt.Errorf("Unable to flush Backend %s: %s", name, err)
+		}
+	}
+
+	if c, ok := old.(io.Closer); ok {
+		if err := c.Close(); err != nil {
+			logInternalf(ERROR, "Unable to close Backend %s: %s", name, err)
+		}
+	}
+
+	return nil
+}
+
+//RemoveBackends removes the objects implementing the Backend interface added
+//with the specified names from the current Logger. Every name that exists is
+//removed, and the returned error lists any names that did not exist.
+func (l *Logger) RemoveBackends(names ...string) error {
+	l.Lock()
+	defer l.Unlock()
+
+	var missing []string
+	for _, name := range names {
+		if _, exists := l.backends[name]; exists {
+			delete(l.backends, name)
+			delete(l.disabled, name)
+		} else {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("Backends with those names do not exist: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+//DisableBackend stops sending LogEntry objects to the Backend added to the
+//current Logger with the specified name, while leaving it added, such as to
+//mute an alerting Backend during a planned maintenance window.
+func (l *Logger) DisableBackend(name string) error {
+	l.Lock()
+	defer l.Unlock()
+	if _, exists := l.backends[name]; !exists {
+		return fmt.Errorf("Backend with that name does not exist: %s", name)
+	}
+	if l.disabled == nil {
+		l.disabled = map[string]struct{}{}
+	}
+	l.disabled[name] = struct{}{}
+	return nil
+}
+
+//EnableBackend resumes sending LogEntry objects to the Backend added to the
+//current Logger with the specified name after it was disabled with
+//DisableBackend.
+func (l *Logger) EnableBackend(name string) error {
+	l.Lock()
+	defer l.Unlock()
+	if _, exists := l.backends[name]; !exists {
 		return fmt.Errorf("Backend with that name does not exist: %s", name)
 	}
+	delete(l.disabled, name)
+	return nil
+}
+
+//SuppressNoBackendWarning turns off the warning logged internally the first
+//time a LogEntry is sent while the current Logger has no Backends added, for
+//setups where discarding every LogEntry is intentional.
+func (l *Logger) SuppressNoBackendWarning() {
+	l.Lock()
+	defer l.Unlock()
+	l.quietNoBackend = true
+}
+
+//AddLevelEnricher adds a function to the current Logger that is called with
+//every LogEntry of the specified LogLevel before it is sent to the backends.
+//This allows for fields to be computed based on the LogLevel of the entry,
+//such as flagging CRITICAL and FATAL entries for alerting.
+func (l *Logger) AddLevelEnricher(level LogLevel, enricher func(*LogEntry)) error {
+	if !validLevel(level) {
+		return fmt.Errorf("Invalid LogLevel: %d", level)
+	}
+	l.Lock()
+	defer l.Unlock()
+	if l.enrichers == nil {
+		l.enrichers = map[LogLevel][]func(*LogEntry){}
+	}
+	l.enrichers[level] = append(l.enrichers[level], enricher)
 	return nil
 }
 
@@ -128,8 +483,8 @@ func (l *Logger) RemoveBackend(name string, backend Backend) error {
 //Backend objects aded to the current Logger if the DEBUG LogLevel currently
 //added to the Logger.
 func (l *Logger) Infof(format string, args ...interface{}) {
-	if l.levelSet(INFO) {
-		l.log(INFO, fmt.Sprintf(format, args...))
+	if skip, ok := l.begin(INFO); ok {
+		l.log(INFO, skip, fmt.Sprintf(format, args...))
 	}
 }
 
@@ -137,8 +492,8 @@ func (l *Logger) Infof(format string, args ...interface{}) {
 //Backend objects aded to the current Logger if the WARN LogLevel currently
 //added to the Logger.
 func (l *Logger) Warnf(format string, args ...interface{}) {
-	if l.levelSet(WARN) {
-		l.log(WARN, fmt.Sprintf(format, args...))
+	if skip, ok := l.begin(WARN); ok {
+		l.log(WARN, skip, fmt.Sprintf(format, args...))
 	}
 }
 
@@ -146,8 +501,8 @@ func (l *Logger) Warnf(format string, args ...interface{}) {
 //Backend objects aded to the current Logger if the ERROR LogLevel currently
 //added to the Logger.
 func (l *Logger) Errorf(format string, args ...interface{}) {
-	if l.levelSet(ERROR) {
-		l.log(ERROR, fmt.Sprintf(format, args...))
+	if skip, ok := l.begin(ERROR); ok {
+		l.log(ERROR, skip, fmt.Sprintf(format, args...))
 	}
 }
 
@@ -155,25 +510,39 @@ func (l *Logger) Errorf(format string, args ...interface{}) {
 //Backend objects aded to the current Logger if the CRITICAL LogLevel currently
 //added to the Logger.
 func (l *Logger) Criticalf(format string, args ...interface{}) {
-	if l.levelSet(CRITICAL) {
-		l.log(CRITICAL, fmt.Sprintf(format, args...))
+	if skip, ok := l.begin(CRITICAL); ok {
+		l.log(CRITICAL, skip, fmt.Sprintf(format, args...))
 	}
 }
 
 //Fatalf logs a formatted string built from the specified args to all added
 //Backend objects aded to the current Logger if the FATAL LogLevel currently
-//added to the Logger, then it will cause the application to os.Exit with status 1
+//added to the Logger, then it will cause the application to os.Exit with status 1.
+//The application exits even if the FATAL LogLevel is not added to the Logger.
+//The stack trace of the calling Goroutine is always captured in the Stack of
+//the LogEntry.
 func (l *Logger) Fatalf(format string, args ...interface{}) {
-	l.log(FATAL, fmt.Sprintf(format, args...))
-	os.Exit(1)
+	if skip, ok := l.begin(FATAL); ok {
+		l.logWith(FATAL, skip, fmt.Sprintf(format, args...), captureStack)
+	}
+	l.exit(1)
 }
 
 //Debugf logs a formatted string built from the specified args to all added
 //Backend objects aded to the current Logger if the DEBUG LogLevel currently
 //added to the Logger.
 func (l *Logger) Debugf(format string, args ...interface{}) {
-	if l.levelSet(DEBUG) {
-		l.log(DEBUG, fmt.Sprintf(format, args...))
+	if skip, ok := l.begin(DEBUG); ok {
+		l.log(DEBUG, skip, fmt.Sprintf(format, args...))
+	}
+}
+
+//Tracef logs a formatted string built from the specified args to all added
+//Backend objects aded to the current Logger if the TRACE LogLevel currently
+//added to the Logger.
+func (l *Logger) Tracef(format string, args ...interface{}) {
+	if skip, ok := l.begin(TRACE); ok {
+		l.log(TRACE, skip, fmt.Sprintf(format, args...))
 	}
 }
 
@@ -181,8 +550,8 @@ func (l *Logger) Debugf(format string, args ...interface{}) {
 //objects aded to the current Logger if the INFO LogLevel currently added
 //to the Logger.
 func (l *Logger) Info(args ...interface{}) {
-	if l.levelSet(INFO) {
-		l.log(INFO, fmt.Sprint(args...))
+	if skip, ok := l.begin(INFO); ok {
+		l.log(INFO, skip, fmt.Sprint(args...))
 	}
 }
 
@@ -190,8 +559,8 @@ func (l *Logger) Info(args ...interface{}) {
 //objects aded to the current Logger if the WARN LogLevel currently added
 //to the Logger.
 func (l *Logger) Warn(args ...interface{}) {
-	if l.levelSet(WARN) {
-		l.log(WARN, fmt.Sprint(args...))
+	if skip, ok := l.begin(WARN); ok {
+		l.log(WARN, skip, fmt.Sprint(args...))
 	}
 }
 
@@ -199,8 +568,8 @@ func (l *Logger) Warn(args ...interface{}) {
 //objects aded to the current Logger if the WARN LogLevel currently added
 //to the Logger.
 func (l *Logger) Error(args ...interface{}) {
-	if l.levelSet(ERROR) {
-		l.log(ERROR, fmt.Sprint(args...))
+	if skip, ok := l.begin(ERROR); ok {
+		l.log(ERROR, skip, fmt.Sprint(args...))
 	}
 }
 
@@ -208,8 +577,8 @@ func (l *Logger) Error(args ...interface{}) {
 //objects aded to the current Logger if the CRITICAL LogLevel currently added
 //to the Logger.
 func (l *Logger) Critical(args ...interface{}) {
-	if l.levelSet(CRITICAL) {
-		l.log(CRITICAL, fmt.Sprint(args...))
+	if skip, ok := l.begin(CRITICAL); ok {
+		l.log(CRITICAL, skip, fmt.Sprint(args...))
 	}
 }
 
@@ -217,23 +586,114 @@ func (l *Logger) Critical(args ...interface{}) {
 //objects aded to the current Logger if the DEBUG LogLevel currently added
 //to the Logger.
 func (l *Logger) Debug(args ...interface{}) {
-	if l.levelSet(DEBUG) {
-		l.log(DEBUG, fmt.Sprint(args...))
+	if skip, ok := l.begin(DEBUG); ok {
+		l.log(DEBUG, skip, fmt.Sprint(args...))
+	}
+}
+
+//Trace logs a string built from the specified args to all added Backend
+//objects aded to the current Logger if the TRACE LogLevel currently added
+//to the Logger.
+func (l *Logger) Trace(args ...interface{}) {
+	if skip, ok := l.begin(TRACE); ok {
+		l.log(TRACE, skip, fmt.Sprint(args...))
 	}
 }
 
 //Fatal logs a string built from the specified args to all added Backend
 //objects aded to the current Logger if the FATAL LogLevel currently added
 //to the Logger, then it will cause the application to os.Exit with status 1.
+//The application exits even if the FATAL LogLevel is not added to the Logger.
+//The stack trace of the calling Goroutine is always captured in the Stack of
+//the LogEntry.
 func (l *Logger) Fatal(args ...interface{}) {
-	l.log(FATAL, fmt.Sprint(args...))
-	os.Exit(1)
+	if skip, ok := l.begin(FATAL); ok {
+		l.logWith(FATAL, skip, fmt.Sprint(args...), captureStack)
+	}
+	l.exit(1)
+}
+
+//exit is the function used by Fatal and Fatalf to exit the application, which
+//is replaced in tests so that they can call Fatal without exiting.
+var exit = os.Exit
+
+//OnFatal sets the function called by Fatal and Fatalf with the exit status 1
+//once the LogEntry has been logged, in place of os.Exit. This allows for
+//backends to be flushed or cleanup code to be run before the application
+//exits, in which case the function should call os.Exit itself, or for Fatal
+//to be tested without exiting. A nil function restores the default of os.Exit.
+func (l *Logger) OnFatal(handler func(code int)) {
+	l.Lock()
+	defer l.Unlock()
+	l.onFatal = handler
+}
+
+//exit is an internal method that calls the function set with OnFatal with the
+//specified exit status, or exits the application if there is none.
+func (l *Logger) exit(code int) {
+	l.Lock()
+	handler := l.onFatal
+	l.Unlock()
+
+	if handler == nil {
+		handler = exit
+	}
+	handler(code)
+}
+
+//Flushf logs a formatted string built from the specified args at the
+//specified LogLevel if it is currently added to the Logger, then flushes every
+//Backend that implements the Flusher interface, returning only once the
+//LogEntry has been delivered. Unlike Fatal, it never exits, so it can be used
+//before exiting or crashing on the caller's own terms. The first error
+//encountered while flushing is returned, if any.
+func (l *Logger) Flushf(level LogLevel, format string, args ...interface{}) error {
+	if skip, ok := l.begin(level); ok {
+		l.log(level, skip, fmt.Sprintf(format, args...))
+	}
+	return l.flushBackends()
+}
+
+//flushBackends sends the summaries of any LogEntry objects suppressed by the
+//rate limits and any pending coalesced LogEntry, then flushes every
+//Backend added to the current Logger that implements the Flusher interface,
+//returning the first error encountered, if any.
+func (l *Logger) flushBackends() error {
+	l.flushRateLimits()
+
+	l.Lock()
+	c := l.coalescer
+	l.Unlock()
+
+	if c != nil {
+		c.flush()
+	}
+
+	l.Lock()
+	var flushers []Flusher
+	for _, backend := range l.backends {
+		if f, ok := backend.(Flusher); ok {
+			flushers = append(flushers, f)
+		}
+	}
+	l.Unlock()
+
+	//Flushing may block on I/O, so it is done without holding the lock, as
+	//in CloseWithTimeout.
+	var firstErr error
+	for _, f := range flushers {
+		if err := f.Flush(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
 }
 
 //validLevel checks the specified LogLevel if it is a valid LogLevel constant
 //and returns true or false based on that check.
 func validLevel(level LogLevel) bool {
-	return level >= INFO && level <= DEBUG
+	_, exists := logLevelValueToName[level]
+	return exists
 }
 
 //levelSet checks the specified LogLevel if it is added to the current Logger
@@ -245,6 +705,54 @@ func (l *Logger) levelSet(level LogLevel) bool {
 	return exists
 }
 
+//begin decides if a LogEntry of the specified LogLevel should be logged by the
+//current Logger, and returns the number of additional stack frames to skip
+//when building it, both read at once while locked. A LogEntry that is decided
+//on is sent even if the configuration of the Logger changes while it is being
+//built and sent, so the decision is never torn between two configurations,
+//and no work is done building a LogEntry that isn't.
+//
+//If the Logger has a component with a LogLevel override set, the LogLevel is
+//checked against the override, otherwise it is checked against the LogLevels
+//added to the Logger. If no backends are added, and no bootstrap buffer is
+//waiting for them, no LogEntry is logged at all, as it would only be
+//discarded.
+func (l *Logger) begin(level LogLevel) (int, bool) {
+	l.Lock()
+	defer l.Unlock()
+	return l.decide(level)
+}
+
+//Enabled reports whether a LogEntry of the specified LogLevel would be logged
+//by the current Logger, such as for an adapter to another logging package to
+//skip the work of building one that wouldn't.
+func (l *Logger) Enabled(level LogLevel) bool {
+	_, ok := l.begin(level)
+	return ok
+}
+
+//decide is the decision of begin. The caller must hold the lock.
+func (l *Logger) decide(level LogLevel) (int, bool) {
+	if len(l.backends) == 0 && l.bootstrap == nil {
+		if l.levelEnabled(level) {
+			l.warnNoBackend()
+		}
+		return 0, false
+	}
+	return l.callerSkip, l.levelEnabled(level)
+}
+
+//levelEnabled is the LogLevel check of begin. The caller must hold the lock.
+func (l *Logger) levelEnabled(level LogLevel) bool {
+	if l.component != "" {
+		if min, exists := l.componentLevels[l.component]; exists {
+			return level >= min
+		}
+	}
+	_, exists := l.logLevels[level]
+	return exists
+}
+
 //backendAdded checks the specified name string of a Backend if it is added
 //to the current Logger and returns true or false based on that check.
 func (l *Logger) backendAdded(name string) bool {
@@ -254,62 +762,201 @@ func (l *Logger) backendAdded(name string) bool {
 	return exists
 }
 
-//log will accept the specified LogLevel and message, build a LogEntry
-//from that information, then send it to all backends added to the
-//current Logger.
-func (l *Logger) log(level LogLevel, message string) {
-	entry := buildLogEntry(level, message)
-	l.sendToBackends(entry)
+//log will accept the specified LogLevel, number of additional stack frames to
+//skip as returned by begin, and message, build a LogEntry from that
+//information, then send it to all backends added to the current Logger.
+func (l *Logger) log(level LogLevel, skip int, message string) {
+	entry := buildLogEntry(level, message, skip)
+	l.dispatch(entry)
+}
+
+//logWith will accept the specified LogLevel, number of additional stack
+//frames to skip as returned by begin, and message, build a LogEntry from that
+//information, call the specified function to add to the LogEntry, then send
+//it to all backends added to the current Logger.
+func (l *Logger) logWith(level LogLevel, skip int, message string, apply func(*LogEntry)) {
+	entry := buildLogEntry(level, message, skip)
+	apply(entry)
+	l.dispatch(entry)
+}
+
+//dispatch will accept a LogEntry built for the current Logger, apply the
+//Logger's configuration to it, then send it through the Middleware chain to
+//all backends added to the current Logger, unless it is dropped by sampling
+//or rate limiting.
+func (l *Logger) dispatch(entry *LogEntry) {
+	if l.sampledOut(entry) || l.rateLimited(entry) {
+		return
+	}
+	l.process(entry)
+}
+
+//process will accept a LogEntry built for the current Logger that was not
+//dropped, apply the Logger's configuration to it, then send it through the
+//Middleware chain to all backends added to the current Logger.
+func (l *Logger) process(entry *LogEntry) {
+	entry.Component = l.component
+	if entry.Transaction == "" {
+		entry.Transaction = l.transaction
+	}
+	if entry.Context == nil {
+		entry.Context = l.ctx
+	}
+	for key, value := range l.fields {
+		if _, exists := entry.Fields[key]; !exists {
+			entry.SetField(key, value)
+		}
+	}
+	if len(l.attachments) > 0 && entry.Level >= CRITICAL {
+		entry.Attachments = l.attachments
+	}
+	l.applyBaggage(entry)
+	l.Lock()
+	entry.Version = l.version
+	entry.Env = l.deployment.env
+	entry.Region = l.deployment.region
+	entry.InstanceID = l.deployment.instanceID
+	if l.hostPID != nil {
+		entry.Host = l.hostPID.host
+		entry.PID = l.hostPID.pid
+	}
+	policy := l.fieldPolicy
+	if !l.rawCallers {
+		entry.Caller = simplifyCaller(entry.Caller)
+	}
+	if l.counts == nil {
+		l.counts = map[LogLevel]uint64{}
+	}
+	l.counts[entry.Level]++
+	l.loggedBytes += uint64(len(entry.Message))
+	l.Unlock()
+	l.enrich(entry)
+	applyFieldPolicy(policy, entry)
+	l.recordSize(len(entry.Message))
+	l.handle(entry)
 }
 
 //buildLogEntry accepts a specified LogLevel and message string, uses
 //the Go runtime to determine where the original call to log originated
 //with the name of the source file, line number, and function block it was
-//called from.
+//called from, along with the time it was logged. The specified number of
+//additional stack frames are skipped, for callers wrapping the Logger.
 //
 //It then returns a pointer to a LogEntry with this
 //information contianed within the fields for consumption by the
 //various objects implementing the Backend interface.
-func buildLogEntry(level LogLevel, message string) *LogEntry {
-	pc, file, line, ok := runtime.Caller(3)
-	fname := "???"
-	path := ""
-	if !ok {
-		file = "???"
-		line = 0
-	} else {
-		if f := runtime.FuncForPC(pc); f != nil {
-			fname = f.Name()
-		}
-		p, f := filepath.Split(file)
-		file = f
-		path = p
+func buildLogEntry(level LogLevel, message string, skip int) *LogEntry {
+	return captureCallSite(4+skip).entry(level, message)
+}
+
+//SetCallerSkip sets the number of additional stack frames skipped when
+//determining the caller of each LogEntry sent by the current Logger, so that
+//wrapping the Logger in helper functions reports the caller of the helper
+//rather than the helper itself. An error is returned if skip is negative.
+func (l *Logger) SetCallerSkip(skip int) error {
+	if skip < 0 {
+		return fmt.Errorf("Caller skip must not be negative: %d", skip)
 	}
-	return &LogEntry{
-		Level:   level,
-		Path:    path,
-		File:    file,
-		Line:    line,
-		Caller:  fname,
-		Message: message,
+	l.Lock()
+	defer l.Unlock()
+	l.callerSkip = skip
+	return nil
+}
+
+//enrich calls all of the enrichers added to the current Logger for the
+//LogLevel of the specified LogEntry.
+func (l *Logger) enrich(entry *LogEntry) {
+	l.Lock()
+	enrichers := l.enrichers[entry.Level]
+	l.Unlock()
+
+	for _, enricher := range enrichers {
+		enricher(entry)
 	}
 }
 
 //sendToBackends accepts a specified LogEntry, then calls the Log
-//function on all backends added to the current Logger.
+//function on all backends added to the current Logger that are not disabled.
+//The backends that receive the LogEntry are picked while the Logger is locked,
+//then called once it is unlocked, so that a slow Backend doesn't hold up
+//changes to the Logger or other Goroutines picking backends.
+//
+//If an alert guard is set and the LogEntry is rate limited by it, the
+//LogEntry is not sent to the guarded backends. The first time a LogEntry is
+//sent while no backends are added, a warning is logged internally. Any
+//routing rules added with Route decide which backends receive the LogEntry.
 func (l *Logger) sendToBackends(entry *LogEntry) {
+	l.inflight.RLock()
+	defer l.inflight.RUnlock()
+
+	for _, backend := range l.receivers(entry) {
+		backend.Log(entry)
+	}
+}
+
+//warnNoBackend logs a warning internally the first time a LogEntry is
+//discarded because no backends are added to the current Logger, unless it is
+//suppressed. The caller must hold the lock.
+func (l *Logger) warnNoBackend() {
+	if l.warnedNoBackend || l.quietNoBackend {
+		return
+	}
+	l.warnedNoBackend = true
+	logInternal(WARN, "Logger has no Backends added, LogEntry objects are being discarded. Add one with AddBackend, or call SuppressNoBackendWarning if this is intentional.")
+}
+
+//receivers returns the backends added to the current Logger that should
+//receive the specified LogEntry, or none if it is held by the bootstrap
+//buffer.
+func (l *Logger) receivers(entry *LogEntry) []Backend {
 	l.Lock()
 	defer l.Unlock()
-	for _, backend := range l.backends {
-		backend.Log(entry)
+
+	if l.holdBootstrap(entry) {
+		return nil
+	}
+
+	if len(l.backends) == 0 {
+		l.warnNoBackend()
+	}
+
+	guard := l.alertGuard
+	if guard != nil && !guard.guards(entry) {
+		guard = nil
+	}
+	receives := l.routeEntry(entry)
+
+	backends := make([]Backend, 0, len(l.backends))
+	var guarded []string
+	for name := range l.backends {
+		if _, disabled := l.disabled[name]; disabled {
+			continue
+		}
+		if !receives(name) {
+			continue
+		}
+		if guard != nil && guard.guarded(name) {
+			guarded = append(guarded, name)
+			continue
+		}
+		backends = append(backends, l.backends[name])
+	}
+
+	//A token is only taken for a LogEntry a guarded Backend would receive, so
+	//that unrelated traffic can't starve the alerting backends.
+	if len(guarded) > 0 && guard.allow() {
+		for _, name := range guarded {
+			backends = append(backends, l.backends[name])
+		}
 	}
+	return backends
 }
 
 //logInternalf is a function that is used to log errors that occur
 //within the scope of the lumberjack package itself. It accepts a
 //LogLevel, a string format, and arbitrary args to format a string
 //and send it using a PrintBackend.
-func logInteralf(level LogLevel, format string, args ...interface{}) {
+func logInternalf(level LogLevel, format string, args ...interface{}) {
 	sendToInternal(level, fmt.Sprintf(format, args...))
 }
 
@@ -326,6 +973,6 @@ func logInternal(level LogLevel, args ...interface{}) {
 //This function is used for internal logging of errors that occur
 //within the scope of the lumberjack package itself.
 func sendToInternal(level LogLevel, message string) {
-	entry := buildLogEntry(level, message)
+	entry := buildLogEntry(level, message, 0)
 	printLog(level, entry)
 }
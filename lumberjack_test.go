@@ -0,0 +1,711 @@
+package lumberjack
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+//captureBackend is a Backend used for testing that records every
+//LogEntry sent to it.
+type captureBackend struct {
+	entries []LogEntry
+	sync.Mutex
+}
+
+func (b *captureBackend) Log(entry *LogEntry) {
+	b.Lock()
+	defer b.Unlock()
+	b.entries = append(b.entries, *entry)
+}
+
+func (b *captureBackend) Entries() []LogEntry {
+	b.Lock()
+	defer b.Unlock()
+	return append([]LogEntry(nil), b.entries...)
+}
+
+func TestAddBackends(t *testing.T) {
+	logger := NewLogger()
+
+	err := logger.AddBackends(map[string]Backend{
+		"one": &captureBackend{},
+		"two": &captureBackend{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect(t, logger.backendAdded("one"), true)
+	expect(t, logger.backendAdded("two"), true)
+}
+
+func TestAddBackendsPartialCollision(t *testing.T) {
+	logger := NewLogger()
+	if err := logger.AddBackend("one", &captureBackend{}); err != nil {
+		t.Fatal(err)
+	}
+
+	err := logger.AddBackends(map[string]Backend{
+		"one":   &captureBackend{},
+		"two":   &captureBackend{},
+		"three": &captureBackend{},
+	})
+	if err == nil {
+		t.Fatal("Expected an error for colliding backend names")
+	}
+
+	// The colliding name should be reported.
+	expect(t, strings.Contains(err.Error(), "one"), true)
+
+	// Nothing from the collection should have been added.
+	expect(t, logger.backendAdded("two"), false)
+	expect(t, logger.backendAdded("three"), false)
+	expect(t, len(logger.backends), 1)
+}
+
+func TestRemoveBackend(t *testing.T) {
+	logger := NewLogger()
+	logger.AddBackend("one", &captureBackend{})
+
+	if err := logger.RemoveBackend("one"); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, logger.backendAdded("one"), false)
+
+	if err := logger.RemoveBackend("one"); err == nil {
+		t.Error("Expected an error removing a backend that does not exist")
+	}
+}
+
+func TestRemoveBackends(t *testing.T) {
+	logger := NewLogger()
+	err := logger.AddBackends(map[string]Backend{
+		"one":   &captureBackend{},
+		"two":   &captureBackend{},
+		"three": &captureBackend{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := logger.RemoveBackends("one", "two"); err != nil {
+		t.Fatal(err)
+	}
+
+	expect(t, logger.backendAdded("one"), false)
+	expect(t, logger.backendAdded("two"), false)
+	expect(t, logger.backendAdded("three"), true)
+
+	// Missing names are reported while existing ones are still removed.
+	err = logger.RemoveBackends("three", "missing")
+	if err == nil {
+		t.Fatal("Expected an error for a missing backend name")
+	}
+	expect(t, strings.Contains(err.Error(), "missing"), true)
+	expect(t, logger.backendAdded("three"), false)
+}
+
+func TestNewStructuredLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newStructuredLogger(&buf)
+
+	logger.Info("Test Info")
+	logger.Debug("Test Debug")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	expect(t, len(lines), 2)
+
+	for _, line := range lines {
+		entry := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Invalid JSON line %q: %s", line, err)
+		}
+
+		for _, key := range []string{"level", "caller", "path", "file", "line", "message"} {
+			if _, exists := entry[key]; !exists {
+				t.Errorf("Expected key %q in JSON line %q", key, line)
+			}
+		}
+	}
+}
+
+func TestAddLevelEnricher(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+	logger.AddLevel(CRITICAL)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	err := logger.AddLevelEnricher(CRITICAL, func(entry *LogEntry) {
+		entry.SetField("alert", true)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger.Info("Test Info")
+	logger.Critical("Test Critical")
+
+	entries := capture.Entries()
+	expect(t, len(entries), 2)
+
+	// The enricher should only have run for the CRITICAL entry.
+	_, exists := entries[0].Fields["alert"]
+	expect(t, exists, false)
+	expect(t, entries[1].Fields["alert"], true)
+
+	if err := logger.AddLevelEnricher(LogLevel(200), func(*LogEntry) {}); err == nil {
+		t.Error("Expected an error for an invalid LogLevel")
+	}
+}
+
+//bufferBackend is a Backend used for testing that buffers every LogEntry
+//sent to it until flushed, simulating a network backend.
+type bufferBackend struct {
+	buffered  []LogEntry
+	delivered []LogEntry
+	closed    bool
+	sync.Mutex
+}
+
+func (b *bufferBackend) Log(entry *LogEntry) {
+	b.Lock()
+	defer b.Unlock()
+	b.buffered = append(b.buffered, *entry)
+}
+
+func (b *bufferBackend) Flush() error {
+	b.Lock()
+	defer b.Unlock()
+	b.delivered = append(b.delivered, b.buffered...)
+	b.buffered = nil
+	return nil
+}
+
+func (b *bufferBackend) Close() error {
+	b.Lock()
+	defer b.Unlock()
+	b.closed = true
+	return nil
+}
+
+func (b *bufferBackend) Delivered() []LogEntry {
+	b.Lock()
+	defer b.Unlock()
+	return append([]LogEntry(nil), b.delivered...)
+}
+
+func TestReplaceBackendDrained(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+
+	old := &bufferBackend{}
+	logger.AddBackend("network", old)
+
+	const writers, perWriter = 4, 250
+
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				logger.Infof("%d-%d", w, i)
+			}
+		}(w)
+	}
+
+	// Switch endpoints while entries are still being logged.
+	replacement := &bufferBackend{}
+	if err := logger.ReplaceBackendDrained("network", replacement); err != nil {
+		t.Fatal(err)
+	}
+
+	wg.Wait()
+	replacement.Flush()
+
+	old.Lock()
+	expect(t, old.closed, true)
+	expect(t, len(old.buffered), 0)
+	old.Unlock()
+
+	// Every entry should have been delivered exactly once.
+	seen := map[string]int{}
+	for _, entry := range append(old.Delivered(), replacement.Delivered()...) {
+		seen[entry.Message]++
+	}
+	expect(t, len(seen), writers*perWriter)
+	for message, count := range seen {
+		if count != 1 {
+			t.Errorf("Expected %q to be delivered once, got %d", message, count)
+		}
+	}
+
+	if err := logger.ReplaceBackendDrained("missing", &bufferBackend{}); err == nil {
+		t.Error("Expected an error replacing a missing backend")
+	}
+}
+
+func TestReplaceBackendDrainedOutsideLock(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+	logger.AddBackend("locking", &lockingFlusher{logger: logger})
+
+	//Would deadlock if the old backend was flushed under the lock.
+	replacement := &captureBackend{}
+	done := make(chan error, 1)
+	go func() { done <- logger.ReplaceBackendDrained("locking", replacement) }()
+
+	select {
+	case err := <-done:
+		expect(t, err, nil)
+	case <-time.After(time.Second * 5):
+		t.Fatal("Timed out replacing the backend")
+	}
+
+	logger.Info("replaced")
+	expect(t, len(replacement.Entries()), 1)
+}
+
+func TestAddBackendInvalidName(t *testing.T) {
+	logger := NewLogger()
+
+	for _, name := range []string{"", "   ", " padded", "padded\t", "__internal"} {
+		if err := logger.AddBackend(name, &captureBackend{}); err == nil {
+			t.Errorf("Expected an error adding a backend named %q", name)
+		}
+	}
+	expect(t, len(logger.backends), 0)
+
+	// Invalid names in a bulk add prevent the whole collection being added.
+	err := logger.AddBackends(map[string]Backend{
+		"valid":      &captureBackend{},
+		"__internal": &captureBackend{},
+	})
+	if err == nil {
+		t.Error("Expected an error adding a backend with a reserved name")
+	}
+	expect(t, len(logger.backends), 0)
+
+	// Names with inner whitespace are fine.
+	if err := logger.AddBackend("my backend", &captureBackend{}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDisableBackend(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+
+	muted := &captureBackend{}
+	other := &captureBackend{}
+	logger.AddBackend("muted", muted)
+	logger.AddBackend("other", other)
+
+	if err := logger.DisableBackend("muted"); err != nil {
+		t.Fatal(err)
+	}
+	logger.Info("during maintenance")
+
+	expect(t, len(muted.Entries()), 0)
+	expect(t, len(other.Entries()), 1)
+	expect(t, logger.backendAdded("muted"), true)
+
+	if err := logger.EnableBackend("muted"); err != nil {
+		t.Fatal(err)
+	}
+	logger.Info("after maintenance")
+
+	expect(t, len(muted.Entries()), 1)
+	expect(t, muted.Entries()[0].Message, "after maintenance")
+	expect(t, len(other.Entries()), 2)
+
+	if err := logger.DisableBackend("missing"); err == nil {
+		t.Error("Expected error disabling a Backend that does not exist")
+	}
+	if err := logger.EnableBackend("missing"); err == nil {
+		t.Error("Expected error enabling a Backend that does not exist")
+	}
+}
+
+func TestNoBackendWarning(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+
+	logger.Info("one")
+	logger.Info("two")
+
+	expect(t, strings.Count(buf.String(), "Logger has no Backends added"), 1)
+
+	buf.Reset()
+	quiet := NewLogger()
+	quiet.AddLevel(INFO)
+	quiet.SuppressNoBackendWarning()
+	quiet.Info("one")
+
+	expect(t, buf.String(), "")
+}
+
+func TestNewLoggerFromLevels(t *testing.T) {
+	parent := NewLogger()
+	parent.AddLevel(INFO)
+	parent.AddLevel(ERROR)
+
+	child := NewLoggerFromLevels(parent)
+	expect(t, child.levelSet(INFO), true)
+	expect(t, child.levelSet(ERROR), true)
+
+	//Changes to the child don't leak into the parent.
+	child.AddLevel(DEBUG)
+	child.RemoveLevel(INFO)
+	expect(t, parent.levelSet(DEBUG), false)
+	expect(t, parent.levelSet(INFO), true)
+
+	//Changes to the parent don't leak into a child.
+	other := NewLoggerFromLevels(parent)
+	parent.AddLevel(WARN)
+	parent.RemoveLevel(ERROR)
+	expect(t, other.levelSet(WARN), false)
+	expect(t, other.levelSet(ERROR), true)
+	expect(t, child.levelSet(WARN), false)
+}
+
+func TestNewLoggerWithDefaultsIsolated(t *testing.T) {
+	logger := NewLoggerWithDefaults()
+	logger.AddLevel(DEBUG)
+	logger.RemoveLevel(INFO)
+
+	_, debug := defaultLevels[DEBUG]
+	_, info := defaultLevels[INFO]
+	expect(t, debug, false)
+	expect(t, info, true)
+	expect(t, NewLoggerWithDefaults().levelSet(INFO), true)
+}
+
+func TestDefaultLevels(t *testing.T) {
+	expect(t, DefaultLevels(), []LogLevel{INFO, WARN, ERROR, CRITICAL, FATAL})
+
+	logger := NewLoggerWithDefaults()
+	for _, level := range []LogLevel{INFO, WARN, ERROR, CRITICAL, FATAL} {
+		expect(t, logger.levelSet(level), true)
+	}
+	expect(t, logger.levelSet(DEBUG), false)
+	expect(t, logger.levelSet(TRACE), false)
+	expect(t, logger.MinLevel(), INFO)
+
+	// The returned slice is a copy.
+	levels := DefaultLevels()
+	levels[0] = TRACE
+	expect(t, DefaultLevels()[0], INFO)
+}
+
+func BenchmarkNewLoggerWithDefaults(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		NewLoggerWithDefaults()
+	}
+}
+
+func BenchmarkNewLoggerFromLevels(b *testing.B) {
+	parent := NewLoggerWithDefaults()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		NewLoggerFromLevels(parent)
+	}
+}
+
+func TestFlushf(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(ERROR)
+
+	buffer := &bufferBackend{}
+	logger.AddBackend("buffer", buffer)
+
+	logger.Error("buffered")
+	expect(t, len(buffer.Delivered()), 0)
+
+	if err := logger.Flushf(ERROR, "shutting down after %d errors", 3); err != nil {
+		t.Fatal(err)
+	}
+
+	delivered := buffer.Delivered()
+	expect(t, len(delivered), 2)
+	expect(t, delivered[1].Message, "shutting down after 3 errors")
+	expect(t, delivered[1].File, "lumberjack_test.go")
+
+	//A LogLevel that isn't added is not logged, but still flushes.
+	logger.Error("buffered again")
+	logger.Flushf(INFO, "not logged")
+	expect(t, len(buffer.Delivered()), 3)
+}
+
+//lockingFlusher is a Flusher that reads the backends of its Logger as it is
+//flushed, which takes the Logger lock.
+type lockingFlusher struct {
+	logger *Logger
+	captureBackend
+}
+
+func (b *lockingFlusher) Flush() error {
+	b.logger.GetBackend("locking")
+	return nil
+}
+
+func TestFlushfOutsideLock(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+	logger.AddBackend("locking", &lockingFlusher{logger: logger})
+
+	//Would deadlock if the backends were flushed under the lock.
+	done := make(chan error, 1)
+	go func() { done <- logger.Flushf(INFO, "flushed") }()
+
+	select {
+	case err := <-done:
+		expect(t, err, nil)
+	case <-time.After(time.Second * 5):
+		t.Fatal("Timed out flushing the backends")
+	}
+}
+
+func TestFatalDisabledLevel(t *testing.T) {
+	var codes []int
+	exit = func(code int) { codes = append(codes, code) }
+	defer func() { exit = os.Exit }()
+
+	logger := NewLogger()
+	logger.AddLevel(FATAL)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	logger.Fatal("logged")
+
+	logger.RemoveLevel(FATAL)
+	logger.Fatal("not logged")
+	logger.Fatalf("not logged %d", 2)
+
+	// The application exits every time, but only logs while FATAL is added.
+	expect(t, codes, []int{1, 1, 1})
+	entries := capture.Entries()
+	expect(t, len(entries), 1)
+	expect(t, entries[0].Message, "logged")
+}
+
+func TestOnFatal(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(FATAL)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	var codes []int
+	var loggedFirst bool
+	logger.OnFatal(func(code int) {
+		codes = append(codes, code)
+		loggedFirst = len(capture.Entries()) == 1
+	})
+
+	logger.Fatalf("shutting down: %s", "disk full")
+
+	expect(t, codes, []int{1})
+	expect(t, loggedFirst, true)
+	expect(t, capture.Entries()[0].Message, "shutting down: disk full")
+}
+
+func TestSetMinLevel(t *testing.T) {
+	logger := NewLoggerFromLevels(NewLoggerWithDefaults())
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	if err := logger.SetMinLevel(WARN); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, logger.MinLevel(), WARN)
+
+	logger.Debug("filtered")
+	logger.Info("filtered")
+	logger.Warn("passed")
+	logger.Error("passed")
+
+	entries := capture.Entries()
+	expect(t, len(entries), 2)
+	expect(t, entries[0].Level, WARN)
+	expect(t, entries[1].Level, ERROR)
+
+	// Adding a level below the minimum lowers it.
+	logger.AddLevel(DEBUG)
+	expect(t, logger.MinLevel(), DEBUG)
+
+	// The shared default levels aren't affected.
+	expect(t, NewLoggerWithDefaults().MinLevel(), INFO)
+
+	if err := logger.SetMinLevel(LogLevel(42)); err == nil {
+		t.Error("Expected an error setting an invalid minimum LogLevel")
+	}
+	expect(t, NewLogger().MinLevel(), FATAL)
+}
+
+func TestGetBackend(t *testing.T) {
+	logger := NewLoggerWithDefaults()
+
+	backend, err := logger.GetBackend("print")
+	if err != nil {
+		t.Fatal(err)
+	}
+	print, ok := backend.(*PrintBackend)
+	expect(t, ok, true)
+	expect(t, print.Verbosity, ERROR)
+
+	if _, err := logger.GetBackend("missing"); err == nil {
+		t.Error("Expected error getting a Backend that does not exist")
+	}
+}
+
+//blockingBackend is a Backend whose Log blocks until it is released.
+type blockingBackend struct {
+	logging chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingBackend) Log(entry *LogEntry) {
+	b.logging <- struct{}{}
+	<-b.release
+}
+
+func TestSlowBackendDoesNotBlockLogger(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+
+	slow := &blockingBackend{logging: make(chan struct{}), release: make(chan struct{})}
+	logger.AddBackend("slow", slow)
+
+	go logger.Info("stuck")
+	<-slow.logging // The slow backend is now in the middle of Log.
+	defer close(slow.release)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		logger.AddLevel(ERROR)
+		logger.AddBackend("capture", &captureBackend{})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Changing the Logger was blocked by a slow backend")
+	}
+	expect(t, logger.levelSet(ERROR), true)
+}
+
+func TestConcurrentLevelChanges(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(ERROR)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			logger.AddLevel(INFO)
+			logger.RemoveLevel(INFO)
+		}
+	}()
+
+	var logged sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		logged.Add(1)
+		go func() {
+			defer logged.Done()
+			for j := 0; j < 200; j++ {
+				logger.Info("maybe")
+				logger.Errorf("always %d", j)
+			}
+		}()
+	}
+	logged.Wait()
+	close(stop)
+	wg.Wait()
+
+	errors := 0
+	for _, entry := range capture.Entries() {
+		expect(t, entry.File, "lumberjack_test.go")
+		if entry.Level == ERROR {
+			errors++
+		}
+	}
+	expect(t, errors, 800)
+}
+
+//countingStringer counts the times it is formatted.
+type countingStringer struct {
+	count int
+}
+
+func (s *countingStringer) String() string {
+	s.count++
+	return "formatted"
+}
+
+func TestNoBackendsSkipsFormatting(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+	logger.SuppressNoBackendWarning()
+
+	arg := &countingStringer{}
+	logger.Infof("%s", arg)
+	logger.Info(arg)
+	expect(t, arg.count, 0)
+
+	logger.AddBackend("discard", DiscardBackend{})
+	logger.Infof("%s", arg)
+	expect(t, arg.count, 1)
+}
+
+func BenchmarkInfofNoBackends(b *testing.B) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+	logger.SuppressNoBackendWarning()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		logger.Infof("benchmark %d %s", i, "args")
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+	logger.SuppressNoBackendWarning()
+
+	//Nothing is logged without a Backend to receive it.
+	expect(t, logger.Enabled(INFO), false)
+
+	logger.AddBackend("discard", DiscardBackend{})
+	expect(t, logger.Enabled(INFO), true)
+	expect(t, logger.Enabled(DEBUG), false)
+}
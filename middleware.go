@@ -0,0 +1,147 @@
+package lumberjack
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"time"
+)
+
+//AccessLogConfig holds the configuration of the LogEntry objects logged by
+//the HTTP access log middleware, with the LogLevel to log at and the names
+//of the fields to put the details of each request in.
+//
+//If Transaction is set, it is called with each request to name the transaction,
+//such as with the route template matched by a router. The name is set on the
+//access LogEntry and attached to the context of the request, so that a Logger
+//derived with WithContext in the handler tags every LogEntry with it too.
+type AccessLogConfig struct {
+	Level         LogLevel
+	MethodField   string
+	PathField     string
+	StatusField   string
+	BytesField    string
+	DurationField string
+	Transaction   func(*http.Request) string
+}
+
+//DefaultAccessLogConfig is the AccessLogConfig used by HTTPAccessMiddleware.
+var DefaultAccessLogConfig = AccessLogConfig{
+	Level:         INFO,
+	MethodField:   "method",
+	PathField:     "path",
+	StatusField:   "status",
+	BytesField:    "bytes",
+	DurationField: "duration_ms",
+}
+
+//responseRecorder wraps an http.ResponseWriter to record the status code
+//and number of bytes written in the response.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+//WriteHeader records the status code before writing it to the wrapped
+//http.ResponseWriter.
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+//Write records the number of bytes written to the wrapped
+//http.ResponseWriter, defaulting the status code to 200 as the
+//http.ResponseWriter does if one has not yet been written.
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(data)
+	r.bytes += n
+	return n, err
+}
+
+//HTTPRecoverMiddleware returns an http.Handler that recovers from any panic
+//that occurs in the specified http.Handler. The panic is logged at CRITICAL
+//with the stack trace and the method, path, and remote address of the request
+//as fields, and an HTTP 500 response is written to the client. The panic is
+//logged with the context.Context of the request, so the transaction name and
+//trace ID it holds are included as well.
+//
+//A panic with http.ErrAbortHandler is not recovered, as it is used to abort
+//the handling of a request on purpose.
+func (l *Logger) HTTPRecoverMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+
+			logger := l.WithContext(r.Context())
+			if skip, ok := logger.begin(CRITICAL); ok {
+				entry := buildLogEntry(CRITICAL, fmt.Sprintf("panic: %v", rec), skip)
+				entry.Stack = string(debug.Stack())
+				entry.SetField("method", r.Method)
+				entry.SetField("path", r.URL.Path)
+				entry.SetField("remote_addr", r.RemoteAddr)
+				logger.dispatch(entry)
+			}
+
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+//HTTPAccessMiddleware returns an http.Handler that logs a LogEntry for every
+//request handled by the specified http.Handler using DefaultAccessLogConfig,
+//with the method, path, status code, bytes written, and duration in
+//milliseconds of the request as fields.
+func (l *Logger) HTTPAccessMiddleware(next http.Handler) http.Handler {
+	return l.HTTPAccessMiddlewareWithConfig(next, DefaultAccessLogConfig)
+}
+
+//HTTPAccessMiddlewareWithConfig returns an http.Handler that logs a LogEntry
+//for every request handled by the specified http.Handler at the LogLevel and
+//with the field names of the specified AccessLogConfig.
+func (l *Logger) HTTPAccessMiddlewareWithConfig(next http.Handler, config AccessLogConfig) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &responseRecorder{ResponseWriter: w}
+
+		var transaction string
+		if config.Transaction != nil {
+			transaction = config.Transaction(r)
+			r = r.WithContext(ContextWithTransaction(r.Context(), transaction))
+		}
+
+		next.ServeHTTP(recorder, r)
+
+		skip, ok := l.begin(config.Level)
+		if !ok {
+			return
+		}
+
+		status := recorder.status
+		if status == 0 {
+			status = http.StatusOK //Nothing was written, so net/http sends a 200.
+		}
+
+		entry := buildLogEntry(config.Level, fmt.Sprintf("%s %s %d", r.Method, r.URL.Path, status), skip)
+		entry.Transaction = transaction
+		entry.SetField(config.MethodField, r.Method)
+		entry.SetField(config.PathField, r.URL.Path)
+		entry.SetField(config.StatusField, status)
+		entry.SetField(config.BytesField, recorder.bytes)
+		entry.SetField(config.DurationField, float64(time.Since(start))/float64(time.Millisecond))
+		l.dispatch(entry)
+	})
+}
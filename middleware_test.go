@@ -0,0 +1,153 @@
+package lumberjack
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTPRecoverMiddleware(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(CRITICAL)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	handler := logger.HTTPRecoverMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("something broke")
+	}))
+
+	req := httptest.NewRequest("POST", "/users/42", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	expect(t, rec.Code, http.StatusInternalServerError)
+
+	entries := capture.Entries()
+	expect(t, len(entries), 1)
+
+	entry := entries[0]
+	expect(t, entry.Level, CRITICAL)
+	expect(t, entry.Message, "panic: something broke")
+	expect(t, entry.Fields["method"], "POST")
+	expect(t, entry.Fields["path"], "/users/42")
+	expect(t, entry.Fields["remote_addr"], req.RemoteAddr)
+	expect(t, strings.Contains(entry.Stack, "TestHTTPRecoverMiddleware"), true)
+}
+
+func TestHTTPRecoverMiddlewareContext(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(CRITICAL)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	handler := logger.HTTPRecoverMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("something broke")
+	}))
+
+	req := httptest.NewRequest("GET", "/orders", nil)
+	ctx := ContextWithTransaction(req.Context(), "list-orders")
+	ctx = ContextWithTraceID(ctx, "abc123")
+	req = req.WithContext(ctx)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	expect(t, rec.Code, http.StatusInternalServerError)
+
+	entries := capture.Entries()
+	expect(t, len(entries), 1)
+
+	entry := entries[0]
+	expect(t, entry.Context, ctx)
+	expect(t, entry.Transaction, "list-orders")
+	expect(t, entry.Fields[TraceIDField], "abc123")
+}
+
+func TestHTTPRecoverMiddlewareNoPanic(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(CRITICAL)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	handler := logger.HTTPRecoverMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	expect(t, rec.Code, http.StatusTeapot)
+	expect(t, len(capture.Entries()), 0)
+}
+
+func TestHTTPAccessMiddleware(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	handler := logger.HTTPAccessMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not here"))
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/missing", nil))
+
+	entries := capture.Entries()
+	expect(t, len(entries), 1)
+
+	entry := entries[0]
+	expect(t, entry.Level, INFO)
+	expect(t, entry.Message, "GET /missing 404")
+	expect(t, entry.Fields["method"], "GET")
+	expect(t, entry.Fields["path"], "/missing")
+	expect(t, entry.Fields["status"], http.StatusNotFound)
+	expect(t, entry.Fields["bytes"], len("not here"))
+
+	duration, ok := entry.Fields["duration_ms"].(float64)
+	expect(t, ok, true)
+	expect(t, duration >= 0, true)
+}
+
+func TestHTTPAccessMiddlewareWithConfig(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(DEBUG)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	config := AccessLogConfig{
+		Level:         DEBUG,
+		MethodField:   "http.method",
+		PathField:     "http.path",
+		StatusField:   "http.status",
+		BytesField:    "http.bytes",
+		DurationField: "http.duration_ms",
+	}
+
+	handler := logger.HTTPAccessMiddlewareWithConfig(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello"))
+	}), config)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("PUT", "/hello", nil))
+
+	entries := capture.Entries()
+	expect(t, len(entries), 1)
+
+	entry := entries[0]
+	expect(t, entry.Level, DEBUG)
+	expect(t, entry.Fields["http.method"], "PUT")
+	expect(t, entry.Fields["http.path"], "/hello")
+	expect(t, entry.Fields["http.status"], http.StatusOK)
+	expect(t, entry.Fields["http.bytes"], 5)
+
+	// Nothing is logged if the LogLevel isn't enabled.
+	logger.RemoveLevel(DEBUG)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("PUT", "/hello", nil))
+	expect(t, len(capture.Entries()), 1)
+}
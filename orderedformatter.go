@@ -0,0 +1,80 @@
+package lumberjack
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+//DefaultKeyOrder is the order of the keys rendered first by an
+//OrderedJSONFormatter with no KeyOrder specified.
+var DefaultKeyOrder = []string{"time", "level", "message"}
+
+//OrderedJSONFormatter implements a Formatter that renders each LogEntry as
+//JSON with its keys in a fixed order, so the output is deterministic and
+//easy to scan. The keys in KeyOrder are rendered first in that order, followed
+//by the remaining keys sorted, with the keys of the structured Fields sorted
+//within them. If KeyOrder is empty, DefaultKeyOrder is used.
+//
+//CompactCaller and FieldNames behave as they do on the JSONBackend, with
+//KeyOrder referring to the keys after they are renamed.
+type OrderedJSONFormatter struct {
+	KeyOrder      []string
+	CompactCaller bool
+	FieldNames    FieldNamer
+}
+
+//Format satisfies the Formatter interface's requirements used for rendering
+//a LogEntry into JSON with ordered keys.
+func (f *OrderedJSONFormatter) Format(entry *LogEntry) ([]byte, error) {
+	data, err := marshalEntryNamed(entry, f.CompactCaller, f.FieldNames)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	order := f.KeyOrder
+	if len(order) == 0 {
+		order = DefaultKeyOrder
+	}
+
+	var keys []string
+	seen := map[string]struct{}{}
+	for _, key := range order {
+		if _, exists := fields[key]; exists {
+			keys = append(keys, key)
+			seen[key] = struct{}{}
+		}
+	}
+
+	var rest []string
+	for key := range fields {
+		if _, exists := seen[key]; !exists {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+	keys = append(keys, rest...)
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(fields[key])
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,40 @@
+package lumberjack
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestOrderedJSONFormatter(t *testing.T) {
+	entry := testobj.Entries[0]
+	entry.Time = newFakeClock().Now()
+	entry.Component = "db"
+	entry.SetField("zeta", 1)
+	entry.SetField("alpha", "a")
+
+	data, err := (&OrderedJSONFormatter{}).Format(&entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect(t, string(data), `{"time":"2015-08-17T12:23:57Z","level":"ERROR","message":"Test Error","caller":"main.main()","component":"db",`+
+		`"fields":{"alpha":"a","zeta":1},"file":"main.go","line":10,"path":"/somewhere"}`)
+}
+
+func TestOrderedJSONFormatterCustomOrder(t *testing.T) {
+	var buf bytes.Buffer
+	backend := NewJSONBackend(&buf)
+	backend.Formatter = &OrderedJSONFormatter{
+		KeyOrder:      []string{"msg", "severity", "src"},
+		CompactCaller: true,
+		FieldNames:    RenameFieldNames(map[string]string{"level": "severity", "message": "msg"}),
+	}
+
+	entry := testobj.Entries[1]
+	entry.Time = newFakeClock().Now()
+	backend.Log(&entry)
+	backend.Log(&entry)
+
+	line := `{"msg":"Test Info","severity":"INFO","src":"main.go:11","time":"2015-08-17T12:23:57Z"}` + "\n"
+	expect(t, buf.String(), line+line)
+}
@@ -0,0 +1,116 @@
+//Package otel adapts the active span of an OpenTelemetry trace to the
+//SpanEventRecorder interface of lumberjack, so that the OTelSpanEventBackend
+//records each LogEntry as an event on the span of go.opentelemetry.io/otel
+//held by the context.Context it was sent with. It is kept in its own module
+//so that the lumberjack package stays free of the dependency for users who
+//don't need it.
+package otel
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/btnmasher/lumberjack"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+//span implements the lumberjack.SpanEventRecorder interface by adding each
+//event to an OpenTelemetry span.
+type span struct {
+	span trace.Span
+}
+
+//NewSpanEventBackend returns an instance of lumberjack.OTelSpanEventBackend
+//that records each LogEntry as an event on the active OpenTelemetry span of
+//its context.Context, and sends LogEntry objects without a recording span to
+//the specified fallback Backend, which may be nil to drop them.
+func NewSpanEventBackend(fallback lumberjack.Backend) *lumberjack.OTelSpanEventBackend {
+	return lumberjack.NewOTelSpanEventBackend(SpanFromContext, fallback)
+}
+
+//SpanFromContext returns the active OpenTelemetry span held by the specified
+//context.Context as a lumberjack.SpanEventRecorder, satisfying the
+//lumberjack.SpanFromContextFunc type. If the context.Context holds no span,
+//one that isn't recording is returned, so the LogEntry is sent to the
+//Fallback Backend instead.
+func SpanFromContext(ctx context.Context) lumberjack.SpanEventRecorder {
+	return span{span: trace.SpanFromContext(ctx)}
+}
+
+//IsRecording returns true if the span is recording events.
+func (s span) IsRecording() bool {
+	return s.span.IsRecording()
+}
+
+//AddEvent adds an event with the specified name and attributes to the span.
+func (s span) AddEvent(name string, attributes map[string]interface{}) {
+	s.span.AddEvent(name, trace.WithAttributes(Attributes(attributes)...))
+}
+
+//Attributes converts the specified attributes of a span event to
+//OpenTelemetry attributes, sorted by key. Strings, booleans, integers, and
+//floats, along with slices of them, keep their type, errors are converted to
+//their message, and any other value is formatted with fmt.
+func Attributes(attributes map[string]interface{}) []attribute.KeyValue {
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	kvs := make([]attribute.KeyValue, 0, len(keys))
+	for _, key := range keys {
+		kvs = append(kvs, keyValue(key, attributes[key]))
+	}
+	return kvs
+}
+
+//keyValue returns the OpenTelemetry attribute with the specified key holding
+//the specified value.
+func keyValue(key string, value interface{}) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int8:
+		return attribute.Int64(key, int64(v))
+	case int16:
+		return attribute.Int64(key, int64(v))
+	case int32:
+		return attribute.Int64(key, int64(v))
+	case int64:
+		return attribute.Int64(key, v)
+	case uint8:
+		return attribute.Int64(key, int64(v))
+	case uint16:
+		return attribute.Int64(key, int64(v))
+	case uint32:
+		return attribute.Int64(key, int64(v))
+	case float32:
+		return attribute.Float64(key, float64(v))
+	case float64:
+		return attribute.Float64(key, v)
+	case []string:
+		return attribute.StringSlice(key, v)
+	case []bool:
+		return attribute.BoolSlice(key, v)
+	case []int:
+		return attribute.IntSlice(key, v)
+	case []int64:
+		return attribute.Int64Slice(key, v)
+	case []float64:
+		return attribute.Float64Slice(key, v)
+	case error:
+		return attribute.String(key, v.Error())
+	case fmt.Stringer:
+		return attribute.String(key, v.String())
+	default:
+		//uint and uint64 may not fit in an int64, so they are formatted too.
+		return attribute.String(key, fmt.Sprint(v))
+	}
+}
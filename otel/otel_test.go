@@ -0,0 +1,86 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/btnmasher/lumberjack"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func expect(t *testing.T, a interface{}, b interface{}) {
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("Expected %v (type %v) - Got %v (type %v)", b, reflect.TypeOf(b), a, reflect.TypeOf(a))
+	}
+}
+
+//captureBackend is a Backend that keeps every LogEntry sent to it.
+type captureBackend struct {
+	entries []lumberjack.LogEntry
+}
+
+func (b *captureBackend) Log(entry *lumberjack.LogEntry) {
+	b.entries = append(b.entries, *entry)
+}
+
+func TestSpanEventBackend(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer provider.Shutdown(context.Background())
+
+	logger := lumberjack.NewLogger()
+	logger.AddLevel(lumberjack.ERROR)
+
+	fallback := &captureBackend{}
+	logger.AddBackend("otel", NewSpanEventBackend(fallback))
+
+	ctx, span := provider.Tracer("test").Start(context.Background(), "request")
+	logger.WithContext(ctx).WithField("attempt", 2).Error("cache miss")
+	span.End()
+
+	//Without a span in the context.Context, the fallback receives it.
+	logger.WithContext(context.Background()).Error("no span")
+
+	spans := recorder.Ended()
+	expect(t, len(spans), 1)
+
+	events := spans[0].Events()
+	expect(t, len(events), 1)
+	expect(t, events[0].Name, "cache miss")
+
+	attributes := map[attribute.Key]attribute.Value{}
+	for _, kv := range events[0].Attributes {
+		attributes[kv.Key] = kv.Value
+	}
+	expect(t, attributes["level"].AsString(), "ERROR")
+	expect(t, attributes["fields.attempt"].AsInt64(), int64(2))
+
+	expect(t, len(fallback.entries), 1)
+	expect(t, fallback.entries[0].Message, "no span")
+}
+
+func TestAttributes(t *testing.T) {
+	kvs := Attributes(map[string]interface{}{
+		"s":     "text",
+		"b":     true,
+		"i":     3,
+		"u":     uint64(4),
+		"f":     1.5,
+		"err":   errors.New("boom"),
+		"names": []string{"a", "b"},
+	})
+
+	expect(t, kvs, []attribute.KeyValue{
+		attribute.Bool("b", true),
+		attribute.String("err", "boom"),
+		attribute.Float64("f", 1.5),
+		attribute.Int("i", 3),
+		attribute.StringSlice("names", []string{"a", "b"}),
+		attribute.String("s", "text"),
+		attribute.String("u", "4"),
+	})
+}
@@ -0,0 +1,81 @@
+package lumberjack
+
+import "context"
+
+//SpanEventRecorder is the part of an OpenTelemetry span used by the
+//OTelSpanEventBackend. The github.com/btnmasher/lumberjack/otel module adapts
+//a trace.Span from go.opentelemetry.io/otel to it, kept apart so that this
+//package stays free of the dependency.
+type SpanEventRecorder interface {
+	IsRecording() bool
+	AddEvent(name string, attributes map[string]interface{})
+}
+
+//SpanFromContextFunc returns the active span held by the specified
+//context.Context, or nil if it holds none.
+type SpanFromContextFunc func(ctx context.Context) SpanEventRecorder
+
+//OTelSpanEventBackend implements a Backend that records each LogEntry as an
+//event on the active span of the context.Context the LogEntry was sent with,
+//as attached with WithContext. The event is named after the message of the
+//LogEntry, and carries the level, caller, and fields of the LogEntry as
+//attributes, with the fields prefixed with "fields.".
+//
+//LogEntry objects sent without an active, recording span are sent to the
+//Fallback Backend instead, if it is set. If AlsoFallback is true, LogEntry
+//objects recorded on a span are sent to the Fallback Backend as well.
+type OTelSpanEventBackend struct {
+	SpanFromContext SpanFromContextFunc
+	Fallback        Backend
+	AlsoFallback    bool
+}
+
+//NewOTelSpanEventBackend returns an instance of OTelSpanEventBackend that
+//finds the active span with the specified SpanFromContextFunc, and sends
+//LogEntry objects without one to the specified fallback Backend, which may
+//be nil to drop them. The SpanFromContext function of the
+//github.com/btnmasher/lumberjack/otel module finds the active span of
+//go.opentelemetry.io/otel.
+func NewOTelSpanEventBackend(spanFromContext SpanFromContextFunc, fallback Backend) *OTelSpanEventBackend {
+	return &OTelSpanEventBackend{SpanFromContext: spanFromContext, Fallback: fallback}
+}
+
+//Log satisfies the Backend interfaces requirements used for accepting
+//LogEntry objects to record as span events.
+func (b *OTelSpanEventBackend) Log(entry *LogEntry) {
+	span := b.span(entry)
+	if span == nil {
+		if b.Fallback != nil {
+			b.Fallback.Log(entry)
+		}
+		return
+	}
+
+	span.AddEvent(entry.Message, spanEventAttributes(entry))
+
+	if b.AlsoFallback && b.Fallback != nil {
+		b.Fallback.Log(entry)
+	}
+}
+
+//span returns the active, recording span of the context.Context of the
+//specified LogEntry, or nil if there is none.
+func (b *OTelSpanEventBackend) span(entry *LogEntry) SpanEventRecorder {
+	if entry.Context == nil || b.SpanFromContext == nil {
+		return nil
+	}
+	span := b.SpanFromContext(entry.Context)
+	if span == nil || !span.IsRecording() {
+		return nil
+	}
+	return span
+}
+
+//spanEventAttributes builds the attributes of the span event for the
+//specified LogEntry.
+func spanEventAttributes(entry *LogEntry) map[string]interface{} {
+	attributes := entry.ToMapPrefixed("fields.")
+	delete(attributes, "message")
+	delete(attributes, "time")
+	return attributes
+}
@@ -0,0 +1,96 @@
+package lumberjack
+
+import (
+	"context"
+	"testing"
+)
+
+//spanEvent is a span event recorded by a recordingSpan.
+type spanEvent struct {
+	name       string
+	attributes map[string]interface{}
+}
+
+//recordingSpan is a SpanEventRecorder that keeps the events added to it.
+type recordingSpan struct {
+	recording bool
+	events    []spanEvent
+}
+
+func (s *recordingSpan) IsRecording() bool { return s.recording }
+
+func (s *recordingSpan) AddEvent(name string, attributes map[string]interface{}) {
+	s.events = append(s.events, spanEvent{name, attributes})
+}
+
+type spanKey struct{}
+
+func testSpanFromContext(ctx context.Context) SpanEventRecorder {
+	span, _ := ctx.Value(spanKey{}).(*recordingSpan)
+	if span == nil {
+		return nil
+	}
+	return span
+}
+
+func TestOTelSpanEventBackend(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+	logger.AddLevel(ERROR)
+
+	fallback := &captureBackend{}
+	logger.AddBackend("otel", NewOTelSpanEventBackend(testSpanFromContext, fallback))
+
+	span := &recordingSpan{recording: true}
+	ctx := context.WithValue(context.Background(), spanKey{}, span)
+
+	logger.WithContext(ctx).ErrorErr(nil, "cache miss", "key", "user:1")
+	logger.Info("no context")
+	logger.WithContext(context.Background()).Info("no span")
+
+	expect(t, len(span.events), 1)
+	event := span.events[0]
+	expect(t, event.name, "cache miss")
+	expect(t, event.attributes["level"], "ERROR")
+	expect(t, event.attributes["fields.key"], "user:1")
+	_, hasMessage := event.attributes["message"]
+	expect(t, hasMessage, false)
+
+	entries := fallback.Entries()
+	expect(t, len(entries), 2)
+	expect(t, entries[0].Message, "no context")
+	expect(t, entries[1].Message, "no span")
+}
+
+func TestOTelSpanEventBackendNotRecording(t *testing.T) {
+	fallback := &captureBackend{}
+	backend := NewOTelSpanEventBackend(testSpanFromContext, fallback)
+
+	span := &recordingSpan{}
+	entry := testobj.Entries[1]
+	entry.Context = context.WithValue(context.Background(), spanKey{}, span)
+	backend.Log(&entry)
+
+	expect(t, len(span.events), 0)
+	expect(t, len(fallback.Entries()), 1)
+}
+
+func TestOTelSpanEventBackendAlsoFallback(t *testing.T) {
+	fallback := &captureBackend{}
+	backend := NewOTelSpanEventBackend(testSpanFromContext, fallback)
+	backend.AlsoFallback = true
+
+	span := &recordingSpan{recording: true}
+	entry := testobj.Entries[1]
+	entry.Context = context.WithValue(context.Background(), spanKey{}, span)
+	backend.Log(&entry)
+
+	expect(t, len(span.events), 1)
+	expect(t, len(fallback.Entries()), 1)
+
+	// Without a fallback, entries without a span are dropped.
+	backend.Fallback = nil
+	entry.Context = nil
+	backend.Log(&entry)
+	expect(t, len(span.events), 1)
+}
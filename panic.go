@@ -0,0 +1,39 @@
+package lumberjack
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+//Panic logs a string built from the specified args to all added Backend
+//objects added to the current Logger if the CRITICAL LogLevel is currently
+//added to the Logger, then panics with the same string. The panic happens even
+//if the CRITICAL LogLevel is not added to the Logger. The stack trace of the
+//calling Goroutine is always captured in the Stack of the LogEntry.
+func (l *Logger) Panic(args ...interface{}) {
+	message := fmt.Sprint(args...)
+	if skip, ok := l.begin(CRITICAL); ok {
+		l.logWith(CRITICAL, skip, message, captureStack)
+	}
+	panic(message)
+}
+
+//Panicf logs a formatted string built from the specified args to all added
+//Backend objects added to the current Logger if the CRITICAL LogLevel is
+//currently added to the Logger, then panics with the same string. The panic
+//happens even if the CRITICAL LogLevel is not added to the Logger. The stack
+//trace of the calling Goroutine is always captured in the Stack of the
+//LogEntry.
+func (l *Logger) Panicf(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	if skip, ok := l.begin(CRITICAL); ok {
+		l.logWith(CRITICAL, skip, message, captureStack)
+	}
+	panic(message)
+}
+
+//captureStack captures the stack trace of the calling Goroutine in the Stack
+//of the specified LogEntry.
+func captureStack(entry *LogEntry) {
+	entry.Stack = string(debug.Stack())
+}
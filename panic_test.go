@@ -0,0 +1,64 @@
+package lumberjack
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+//recoverPanic calls the specified function and returns the value it panicked
+//with, if any.
+func recoverPanic(f func()) (rec interface{}) {
+	defer func() { rec = recover() }()
+	f()
+	return nil
+}
+
+func TestPanicCapturesStack(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(ERROR)
+	logger.AddLevel(CRITICAL)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	logger.Error("no stack")
+	rec := recoverPanic(func() { logger.Panicf("broken %d", 1) })
+	expect(t, rec, "broken 1")
+
+	entries := capture.Entries()
+	expect(t, len(entries), 2)
+	expect(t, entries[0].Stack, "")
+	expect(t, entries[1].Level, CRITICAL)
+	expect(t, entries[1].Message, "broken 1")
+	expect(t, strings.Contains(entries[1].Stack, "TestPanicCapturesStack"), true)
+}
+
+func TestPanicDisabledLevel(t *testing.T) {
+	logger := NewLogger()
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	rec := recoverPanic(func() { logger.Panic("not logged") })
+	expect(t, rec, "not logged")
+	expect(t, len(capture.Entries()), 0)
+}
+
+func TestFatalCapturesStack(t *testing.T) {
+	exit = func(int) {}
+	defer func() { exit = os.Exit }()
+
+	logger := NewLogger()
+	logger.AddLevel(FATAL)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	logger.Fatal("crashed")
+
+	entries := capture.Entries()
+	expect(t, len(entries), 1)
+	expect(t, entries[0].File, "panic_test.go")
+	expect(t, strings.Contains(entries[0].Stack, "TestFatalCapturesStack"), true)
+}
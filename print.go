@@ -0,0 +1,56 @@
+package lumberjack
+
+import "fmt"
+
+//Print logs a string built from the specified args, as with fmt.Sprint, to
+//all added Backend objects added to the current Logger if the LogLevel set
+//with SetPrintLevel, INFO by default, is currently added to the Logger. This
+//along with Printf and Println allows for the Logger to be used in place of a
+//standard library log.Logger.
+func (l *Logger) Print(args ...interface{}) {
+	if level, skip, ok := l.beginPrint(); ok {
+		l.log(level, skip, fmt.Sprint(args...))
+	}
+}
+
+//Printf logs a formatted string built from the specified args to all added
+//Backend objects added to the current Logger if the LogLevel set with
+//SetPrintLevel, INFO by default, is currently added to the Logger.
+func (l *Logger) Printf(format string, args ...interface{}) {
+	if level, skip, ok := l.beginPrint(); ok {
+		l.log(level, skip, fmt.Sprintf(format, args...))
+	}
+}
+
+//Println logs a string built from the specified args, as with fmt.Sprintln
+//without the trailing newline, to all added Backend objects added to the
+//current Logger if the LogLevel set with SetPrintLevel, INFO by default, is
+//currently added to the Logger.
+func (l *Logger) Println(args ...interface{}) {
+	if level, skip, ok := l.beginPrint(); ok {
+		message := fmt.Sprintln(args...)
+		l.log(level, skip, message[:len(message)-1])
+	}
+}
+
+//SetPrintLevel sets the LogLevel the LogEntry objects of Print, Printf, and
+//Println are logged at.
+func (l *Logger) SetPrintLevel(level LogLevel) error {
+	if !validLevel(level) {
+		return fmt.Errorf("Invalid LogLevel: %d", level)
+	}
+	l.Lock()
+	defer l.Unlock()
+	l.printLevel = level
+	return nil
+}
+
+//beginPrint decides if a LogEntry of the LogLevel the bare Print methods log
+//at should be logged, as with begin, returning that LogLevel along with the
+//decision, all read at once while locked.
+func (l *Logger) beginPrint() (LogLevel, int, bool) {
+	l.Lock()
+	defer l.Unlock()
+	skip, ok := l.decide(l.printLevel)
+	return l.printLevel, skip, ok
+}
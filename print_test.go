@@ -0,0 +1,78 @@
+package lumberjack
+
+import "testing"
+
+func TestPrint(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	logger.Print("a", "b")
+	logger.Printf("%d items", 3)
+	logger.Println("a", "b")
+
+	entries := capture.Entries()
+	expect(t, len(entries), 3)
+	expect(t, entries[0].Level, INFO)
+	expect(t, entries[0].Message, "ab")
+	expect(t, entries[1].Message, "3 items")
+	expect(t, entries[2].Message, "a b")
+	expect(t, entries[2].File, "print_test.go")
+}
+
+func TestSetPrintLevel(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	if err := logger.SetPrintLevel(DEBUG); err != nil {
+		t.Fatal(err)
+	}
+
+	// DEBUG isn't added, so Print is filtered out.
+	logger.Print("hidden")
+	expect(t, len(capture.Entries()), 0)
+
+	logger.AddLevel(DEBUG)
+	logger.Printf("shown %s", "now")
+
+	entries := capture.Entries()
+	expect(t, len(entries), 1)
+	expect(t, entries[0].Level, DEBUG)
+	expect(t, entries[0].Message, "shown now")
+
+	if err := logger.SetPrintLevel(LogLevel(99)); err == nil {
+		t.Error("Expected an error setting an invalid print LogLevel")
+	}
+}
+
+func TestPrintConcurrentSetPrintLevel(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 500; i++ {
+			logger.SetPrintLevel(DEBUG)
+			logger.SetPrintLevel(INFO)
+		}
+	}()
+
+	for i := 0; i < 500; i++ {
+		logger.Print("entry")
+	}
+	<-done
+
+	// DEBUG isn't added, so only entries decided on at INFO are logged.
+	for _, entry := range capture.Entries() {
+		expect(t, entry.Level, INFO)
+	}
+}
@@ -1,26 +1,187 @@
 package lumberjack
 
-import "log"
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+	"unicode/utf8"
+)
 
 //PrintBackend implements a console printing Backend that currently
 //offers two predefined formats based on the Verbosity specified.
+//
+//If MaxLineWidth is set, the message of any line wider than MaxLineWidth
+//characters is truncated, keeping the level and caller prefix intact and
+//appending the number of bytes that were cut off.
+//
+//Each line starts with the time of the LogEntry in the RFC3339 format with
+//nanoseconds, and ends with the structured Fields of the LogEntry as
+//key=value pairs sorted by key. The LogLevel of each line is rendered in the
+//LevelCase and LevelStyle specified, such as "info" or "I" rather than the
+//default "INFO".
+//
+//If Template is set, each line is instead rendered by executing it as a
+//text/template against the LogEntry, such as "{{.Level}} {{.Message}}", which
+//exposes every field of the LogEntry including Time, Caller, Path, File, Line,
+//and Fields.
+//
+//Lines are written to Writer, or to os.Stderr if it is not set.
 type PrintBackend struct {
-	Verbosity LogLevel
+	Verbosity    LogLevel
+	MaxLineWidth int
+	LevelCase    LevelCase
+	LevelStyle   LevelStyle
+	Template     string
+	Writer       io.Writer
+
+	tmpl *template.Template
+	once sync.Once
+	sync.Mutex
+}
+
+//NewPrintBackend returns an instance of PrintBackend with the specified
+//Verbosity that renders each line with the specified Template, or with the
+//default format if it is empty. An error is returned if the Template can't be
+//parsed.
+func NewPrintBackend(verbosity LogLevel, tmpl string) (*PrintBackend, error) {
+	b := &PrintBackend{Verbosity: verbosity, Template: tmpl}
+	if tmpl == "" {
+		return b, nil
+	}
+
+	parsed, err := template.New("print").Parse(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("Print Backend: unable to parse template: %s", err)
+	}
+	b.tmpl = parsed
+	return b, nil
 }
 
 //Log satisfies the Backend interfaces requirements used for accepting
 //LogEntry objects to print out to the console.
 func (b *PrintBackend) Log(entry *LogEntry) {
-	//TODO: Custom Formatting Templates
-	printLog(b.Verbosity, entry)
+	if tmpl := b.template(); tmpl != nil {
+		var line strings.Builder
+		if err := tmpl.Execute(&line, entry); err != nil {
+			logInternalf(ERROR, "Print Backend: unable to execute template: %s", err)
+			return
+		}
+		if b.MaxLineWidth > 0 {
+			b.write(truncateLine(line.String(), 0, b.MaxLineWidth))
+			return
+		}
+		b.write(line.String())
+		return
+	}
+
+	fields := formatFields(entry.Fields)
+	line := formatEntry(b.Verbosity, entry.Level.format(b.LevelCase, b.LevelStyle), entry) + fields
+	if !entry.Time.IsZero() {
+		line = entry.Time.Format(time.RFC3339Nano) + " " + line
+	}
+	if b.MaxLineWidth > 0 {
+		line = truncateLine(line, len(line)-len(entry.Message)-len(fields), b.MaxLineWidth)
+	}
+	b.write(line)
+}
+
+//write is an internal method that writes the specified line followed by a
+//newline to the Writer of the PrintBackend.
+func (b *PrintBackend) write(line string) {
+	b.Lock()
+	defer b.Unlock()
+
+	w := b.Writer
+	if w == nil {
+		w = os.Stderr
+	}
+	if _, err := io.WriteString(w, line+"\n"); err != nil {
+		logInternalf(ERROR, "Print Backend: unable to write LogEntry: %s", err)
+	}
+}
+
+//template is an internal method that returns the parsed Template, parsing it
+//on first use if the PrintBackend wasn't created with NewPrintBackend. If the
+//Template is empty or can't be parsed, nil is returned so that the default
+//format is used.
+func (b *PrintBackend) template() *template.Template {
+	b.once.Do(func() {
+		if b.tmpl != nil || b.Template == "" {
+			return
+		}
+		parsed, err := template.New("print").Parse(b.Template)
+		if err != nil {
+			logInternalf(ERROR, "Print Backend: unable to parse template: %s", err)
+			return
+		}
+		b.tmpl = parsed
+	})
+	return b.tmpl
 }
 
 //printLog is an internal function to print the log to the console with
 //a predefined format determined by the verbosity LogLevel paramter.
 func printLog(verbosity LogLevel, entry *LogEntry) {
+	log.Print(formatEntry(verbosity, entry.Level.String(), entry))
+}
+
+//formatEntry is an internal function to format a LogEntry as a line of text
+//with a predefined format determined by the verbosity LogLevel parameter, and
+//the LogLevel of the LogEntry rendered as the specified level string.
+func formatEntry(verbosity LogLevel, level string, entry *LogEntry) string {
 	if entry.Level >= verbosity {
-		log.Printf("(%s) @ %s() %s:%v: %s", entry.Level, entry.Caller, entry.File, entry.Line, entry.Message)
-	} else {
-		log.Printf("(%s) @ %s(): %s", entry.Level, entry.Caller, entry.Message)
+		return fmt.Sprintf("(%s) @ %s() %s:%v: %s", level, entry.Caller, entry.File, entry.Line, entry.Message)
+	}
+	return fmt.Sprintf("(%s) @ %s(): %s", level, entry.Caller, entry.Message)
+}
+
+//truncateLine is an internal function that truncates a line of text to the
+//specified width in characters, never cutting within the first prefixLen bytes
+//of the line. Multi-byte UTF-8 characters count as one character and are never
+//split, and ANSI escape sequences don't count towards the width and are never
+//split. If any ANSI escape sequence was kept, a reset sequence is appended so
+//that colors don't bleed past the line. The number of bytes cut off is appended
+//to a truncated line.
+func truncateLine(line string, prefixLen int, width int) string {
+	visible := 0
+	sawANSI := false
+
+	i := 0
+	for i < len(line) {
+		if line[i] == '\x1b' && i+1 < len(line) && line[i+1] == '[' {
+			j := i + 2
+			for j < len(line) && (line[j] < 0x40 || line[j] > 0x7e) {
+				j++ //Skip the parameter bytes up to the final byte.
+			}
+			if j < len(line) {
+				j++
+			}
+			i = j
+			sawANSI = true
+			continue
+		}
+
+		if visible >= width && i >= prefixLen {
+			break
+		}
+
+		_, size := utf8.DecodeRuneInString(line[i:])
+		i += size
+		visible++
+	}
+
+	if i >= len(line) {
+		return line
+	}
+
+	truncated := line[:i]
+	if sawANSI {
+		truncated += "\x1b[0m"
 	}
+	return fmt.Sprintf("%s... [%d bytes truncated]", truncated, len(line)-i)
 }
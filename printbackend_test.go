@@ -0,0 +1,147 @@
+package lumberjack
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestTruncateLine(t *testing.T) {
+	// Short lines are left alone.
+	expect(t, truncateLine("(INFO) @ main(): hi", 17, 40), "(INFO) @ main(): hi")
+
+	// Long messages are cut with the bytes cut off appended.
+	expect(t, truncateLine("(INFO) @ main(): 0123456789", 17, 20), "(INFO) @ main(): 012... [7 bytes truncated]")
+
+	// The prefix is kept even if it is wider than the width.
+	expect(t, truncateLine("(INFO) @ main(): 0123456789", 17, 5), "(INFO) @ main(): ... [10 bytes truncated]")
+}
+
+func TestTruncateLineMultiByte(t *testing.T) {
+	line := "(INFO) @ main(): " + strings.Repeat("é", 10)
+
+	truncated := truncateLine(line, 17, 20)
+	expect(t, utf8.ValidString(truncated), true)
+	expect(t, truncated, "(INFO) @ main(): ééé... [14 bytes truncated]")
+}
+
+func TestTruncateLineANSI(t *testing.T) {
+	// Color sequences don't count towards the width and aren't split.
+	line := "(INFO) @ main(): \x1b[31mred\x1b[0m and more text"
+
+	truncated := truncateLine(line, 17, 22)
+	expect(t, truncated, "(INFO) @ main(): \x1b[31mred\x1b[0m a\x1b[0m... [12 bytes truncated]")
+
+	// Cutting right after an opened color resets it.
+	line = "(INFO) @ main(): \x1b[1;32mgreen text"
+	truncated = truncateLine(line, 17, 17)
+	expect(t, truncated, "(INFO) @ main(): \x1b[1;32m\x1b[0m... [10 bytes truncated]")
+}
+
+func TestPrintBackendMaxLineWidth(t *testing.T) {
+	var buf bytes.Buffer
+
+	backend := &PrintBackend{Verbosity: ERROR, MaxLineWidth: 30, Writer: &buf}
+
+	entry := testobj.Entries[1]
+	entry.Message = strings.Repeat("x", 100)
+	backend.Log(&entry)
+
+	expect(t, buf.String(), "(INFO) @ main.main()(): xxxxxx... [94 bytes truncated]\n")
+}
+
+func TestLevelFormat(t *testing.T) {
+	cases := []struct {
+		level     LogLevel
+		levelCase LevelCase
+		style     LevelStyle
+		want      string
+	}{
+		{INFO, LevelUpper, LevelFull, "INFO"},
+		{INFO, LevelLower, LevelFull, "info"},
+		{INFO, LevelUpper, LevelShort, "I"},
+		{INFO, LevelLower, LevelShort, "i"},
+		{WARN, LevelLower, LevelFull, "warn"},
+		{WARN, LevelUpper, LevelShort, "W"},
+		{CRITICAL, LevelLower, LevelShort, "c"},
+		{DEBUG, LevelUpper, LevelShort, "D"},
+		{ERROR, LevelLower, LevelFull, "error"},
+	}
+
+	for _, c := range cases {
+		expect(t, c.level.format(c.levelCase, c.style), c.want)
+	}
+}
+
+func TestPrintBackendLevelFormat(t *testing.T) {
+	var buf bytes.Buffer
+
+	backend := &PrintBackend{Verbosity: ERROR, LevelCase: LevelLower, LevelStyle: LevelShort, Writer: &buf}
+
+	entry := testobj.Entries[1]
+	backend.Log(&entry)
+
+	expect(t, buf.String(), "(i) @ main.main()(): Test Info\n")
+}
+
+func TestPrintBackendTime(t *testing.T) {
+	var buf bytes.Buffer
+
+	entry := testobj.Entries[1]
+	entry.Time = time.Date(2015, 8, 17, 12, 23, 57, 123456789, time.UTC)
+	(&PrintBackend{Verbosity: ERROR, Writer: &buf}).Log(&entry)
+
+	expect(t, buf.String(), "2015-08-17T12:23:57.123456789Z (INFO) @ main.main()(): Test Info\n")
+}
+
+func TestPrintBackendTemplate(t *testing.T) {
+	var buf bytes.Buffer
+
+	backend, err := NewPrintBackend(ERROR, "[{{.Level}}] {{.File}}:{{.Line}} {{.Message}} {{.Time.Year}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend.Writer = &buf
+
+	entry := testobj.Entries[1]
+	entry.Time = time.Date(2015, 8, 17, 12, 23, 57, 0, time.UTC)
+	backend.Log(&entry)
+	expect(t, buf.String(), "[INFO] main.go:11 Test Info 2015\n")
+
+	// Templates set without the constructor are parsed on first use.
+	buf.Reset()
+	(&PrintBackend{Template: "{{.Message}}!", Writer: &buf}).Log(&entry)
+	expect(t, buf.String(), "Test Info!\n")
+}
+
+func TestPrintBackendTemplateDefault(t *testing.T) {
+	var buf bytes.Buffer
+
+	backend, err := NewPrintBackend(ERROR, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend.Writer = &buf
+
+	entry := testobj.Entries[1]
+	backend.Log(&entry)
+	expect(t, buf.String(), "(INFO) @ main.main()(): Test Info\n")
+
+	if _, err := NewPrintBackend(ERROR, "{{.Message"); err == nil {
+		t.Error("Expected an error parsing an invalid template")
+	}
+}
+
+func TestPrintBackendWriter(t *testing.T) {
+	var first, second bytes.Buffer
+
+	entry := testobj.Entries[0]
+	(&PrintBackend{Verbosity: ERROR, Writer: &first}).Log(&entry)
+	(&PrintBackend{Verbosity: FATAL, Writer: &second}).Log(&entry)
+
+	// Each PrintBackend writes to its own Writer.
+	expect(t, first.String(), "(ERROR) @ main.main()() main.go:10: Test Error\n")
+	expect(t, second.String(), "(ERROR) @ main.main()(): Test Error\n")
+}
@@ -0,0 +1,192 @@
+package lumberjack
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+//rateLimitWindow is how long after a LogEntry is first suppressed by a rate
+//limit that the summary of those suppressed is sent, if no LogEntry of that
+//LogLevel is let through sooner.
+const rateLimitWindow = time.Second
+
+//rateLimiter is a token bucket limiting the LogEntry objects of a LogLevel to
+//a number per second, counting those it suppresses. It starts full, so the
+//first burst of up to perSecond LogEntry objects always goes through.
+type rateLimiter struct {
+	perSecond  float64
+	tokens     float64
+	last       time.Time
+	suppressed int
+	callsite   LogEntry
+	window     time.Duration
+	timer      *time.Timer
+}
+
+//newRateLimiter returns a rateLimiter with a full bucket letting through the
+//specified number of LogEntry objects per second.
+func newRateLimiter(perSecond float64) *rateLimiter {
+	return &rateLimiter{perSecond: perSecond, tokens: perSecond, window: rateLimitWindow}
+}
+
+//allow takes a token from the bucket and returns true if one was available,
+//otherwise counting the LogEntry as suppressed.
+func (r *rateLimiter) allow(now time.Time) bool {
+	if !r.last.IsZero() {
+		r.tokens += now.Sub(r.last).Seconds() * r.perSecond
+		if r.tokens > r.perSecond {
+			r.tokens = r.perSecond
+		}
+	}
+	r.last = now
+
+	if r.tokens < 1 {
+		r.suppressed++
+		return false
+	}
+	r.tokens--
+	return true
+}
+
+//suppress records the source information of the specified suppressed
+//LogEntry for the summary, and starts the timer calling the specified
+//function to send the summary once the window elapses, if not started.
+func (r *rateLimiter) suppress(entry *LogEntry, flush func()) {
+	r.callsite = LogEntry{
+		Level:  entry.Level,
+		Caller: entry.Caller,
+		Path:   entry.Path,
+		File:   entry.File,
+		Line:   entry.Line,
+	}
+	if r.timer == nil {
+		r.timer = time.AfterFunc(r.window, flush)
+	}
+}
+
+//summary returns a LogEntry summarizing the number of LogEntry objects
+//suppressed since the last summary at the specified time, resetting that
+//count, or nil if none were suppressed.
+func (r *rateLimiter) summary(now time.Time) *LogEntry {
+	if r.timer != nil {
+		r.timer.Stop()
+		r.timer = nil
+	}
+	if r.suppressed == 0 {
+		return nil
+	}
+
+	summary := r.callsite
+	summary.Time = now
+	summary.Message = fmt.Sprintf("%d messages suppressed by the rate limit of %s", r.suppressed, summary.Level)
+	summary.SetField("suppressed", r.suppressed)
+	r.suppressed = 0
+	return &summary
+}
+
+//SetRateLimit limits the LogEntry objects of the specified LogLevel sent by
+//the current Logger to the specified number per second, so that a misbehaving
+//component can't drown out every other log. Any excess is dropped, and a
+//summary LogEntry with the number that were suppressed in the "suppressed"
+//field is sent ahead of the next LogEntry of that LogLevel let through, or
+//after a second if there is none, or when the Logger is flushed or closed. A
+//perSecond of 0 removes the limit, and it must not be negative.
+func (l *Logger) SetRateLimit(level LogLevel, perSecond int) error {
+	if !validLevel(level) {
+		return fmt.Errorf("Invalid LogLevel: %d", level)
+	}
+	if perSecond < 0 {
+		return fmt.Errorf("Rate limit must not be negative: %d", perSecond)
+	}
+
+	now := l.now()
+
+	l.Lock()
+	var summary *LogEntry
+	if old := l.rateLimits[level]; old != nil {
+		summary = old.summary(now)
+	}
+
+	if perSecond == 0 {
+		delete(l.rateLimits, level)
+	} else {
+		if l.rateLimits == nil {
+			l.rateLimits = map[LogLevel]*rateLimiter{}
+		}
+		l.rateLimits[level] = newRateLimiter(float64(perSecond))
+	}
+	l.Unlock()
+
+	if summary != nil {
+		l.process(summary)
+	}
+	return nil
+}
+
+//rateLimited returns true if the specified LogEntry is dropped by the rate
+//limit of its LogLevel. If LogEntry objects were suppressed before it, a
+//summary LogEntry is sent ahead of it.
+func (l *Logger) rateLimited(entry *LogEntry) bool {
+	now := l.now()
+
+	l.Lock()
+	limiter := l.rateLimits[entry.Level]
+	if limiter == nil {
+		l.Unlock()
+		return false
+	}
+
+	if !limiter.allow(now) {
+		level := entry.Level
+		limiter.suppress(entry, func() { l.flushRateLimit(level) })
+		l.Unlock()
+		return true
+	}
+
+	summary := limiter.summary(now)
+	l.Unlock()
+
+	if summary != nil {
+		l.process(summary)
+	}
+	return false
+}
+
+//flushRateLimit sends the summary of the LogEntry objects suppressed by the
+//rate limit of the specified LogLevel, if any, once its window elapses.
+func (l *Logger) flushRateLimit(level LogLevel) {
+	now := l.now()
+
+	l.Lock()
+	var summary *LogEntry
+	if limiter := l.rateLimits[level]; limiter != nil {
+		summary = limiter.summary(now)
+	}
+	l.Unlock()
+
+	if summary != nil {
+		l.process(summary)
+	}
+}
+
+//flushRateLimits sends the summaries of the LogEntry objects suppressed by
+//every rate limit of the current Logger, if any, such as when it is flushed.
+func (l *Logger) flushRateLimits() {
+	now := l.now()
+
+	l.Lock()
+	var summaries []*LogEntry
+	for _, limiter := range l.rateLimits {
+		if summary := limiter.summary(now); summary != nil {
+			summaries = append(summaries, summary)
+		}
+	}
+	l.Unlock()
+
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Level < summaries[j].Level })
+
+	for _, summary := range summaries {
+		l.process(summary)
+	}
+}
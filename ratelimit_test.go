@@ -0,0 +1,140 @@
+package lumberjack
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSetRateLimit(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+	logger.AddLevel(ERROR)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	clock := newFakeClock()
+	logger.clock = clock
+
+	if err := logger.SetRateLimit(ERROR, 10); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 100; i++ {
+		logger.Error("storm")
+		logger.Info("unlimited")
+	}
+
+	counts := map[LogLevel]int{}
+	for _, entry := range capture.Entries() {
+		counts[entry.Level]++
+	}
+	expect(t, counts, map[LogLevel]int{ERROR: 10, INFO: 100})
+
+	//Once the bucket refills, the suppressed count is summarized first.
+	clock.Advance(time.Second)
+	logger.Error("after one second")
+
+	entries := capture.Entries()
+	expect(t, len(entries), 112)
+	expect(t, entries[110].Level, ERROR)
+	expect(t, entries[110].Message, "90 messages suppressed by the rate limit of ERROR")
+	expect(t, entries[110].Fields["suppressed"], 90)
+	expect(t, entries[111].Message, "after one second")
+
+	//Removing the limit lets everything through.
+	if err := logger.SetRateLimit(ERROR, 0); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		logger.Error("unlimited")
+	}
+	expect(t, len(capture.Entries()), 132)
+}
+
+func TestSetRateLimitConcurrent(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(ERROR)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	logger.clock = newFakeClock()
+	logger.SetRateLimit(ERROR, 10)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				logger.Error("storm")
+			}
+		}()
+	}
+	wg.Wait()
+
+	expect(t, len(capture.Entries()), 10)
+}
+
+func TestSetRateLimitInvalid(t *testing.T) {
+	logger := NewLogger()
+	if err := logger.SetRateLimit(LogLevel(99), 10); err == nil {
+		t.Error("Expected error for an invalid LogLevel")
+	}
+	if err := logger.SetRateLimit(ERROR, -1); err == nil {
+		t.Error("Expected error for a negative rate limit")
+	}
+}
+
+func TestRateLimitSummaryOnClose(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(ERROR)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	logger.clock = newFakeClock()
+	logger.SetRateLimit(ERROR, 2)
+
+	//The burst ends with nothing logged after it.
+	for i := 0; i < 5; i++ {
+		logger.Error("storm")
+	}
+	expect(t, len(capture.Entries()), 2)
+
+	if _, err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	entries := capture.Entries()
+	expect(t, len(entries), 3)
+	expect(t, entries[2].Message, "3 messages suppressed by the rate limit of ERROR")
+	expect(t, entries[2].Fields["suppressed"], 3)
+}
+
+func TestRateLimitSummaryAfterWindow(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(ERROR)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	logger.SetRateLimit(ERROR, 1)
+	logger.rateLimits[ERROR].window = time.Millisecond * 10
+
+	//The burst ends with nothing logged after it.
+	for i := 0; i < 4; i++ {
+		logger.Error("storm")
+	}
+
+	deadline := time.Now().Add(time.Second * 5)
+	for len(capture.Entries()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond * 5)
+	}
+
+	entries := capture.Entries()
+	expect(t, len(entries), 2)
+	expect(t, entries[1].Message, "3 messages suppressed by the rate limit of ERROR")
+}
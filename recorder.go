@@ -0,0 +1,61 @@
+package lumberjack
+
+import "sync"
+
+//MergedRecorder collects the LogEntry objects of several Loggers into a
+//single ordered list, each tagged with the name of the Logger it came from.
+//It is intended for testing systems made of several subsystems that each have
+//their own Logger, so that assertions can be made against a single list.
+type MergedRecorder struct {
+	entries []RecordedEntry
+	sync.Mutex
+}
+
+//RecordedEntry is a LogEntry captured by a MergedRecorder, along with the
+//name of the Logger it came from.
+type RecordedEntry struct {
+	Logger string
+	Entry  LogEntry
+}
+
+//NewMergedRecorder returns an empty instance of MergedRecorder.
+func NewMergedRecorder() *MergedRecorder {
+	return &MergedRecorder{}
+}
+
+//Backend returns a Backend to be added to a Logger that records a copy of
+//each LogEntry into the current MergedRecorder, tagged with the specified
+//Logger name.
+func (r *MergedRecorder) Backend(name string) Backend {
+	return &recorderBackend{recorder: r, name: name}
+}
+
+//Entries returns a copy of every RecordedEntry recorded so far, in the order
+//they were logged.
+func (r *MergedRecorder) Entries() []RecordedEntry {
+	r.Lock()
+	defer r.Unlock()
+	return append([]RecordedEntry(nil), r.entries...)
+}
+
+//Reset discards every RecordedEntry recorded so far.
+func (r *MergedRecorder) Reset() {
+	r.Lock()
+	defer r.Unlock()
+	r.entries = nil
+}
+
+//recorderBackend implements a Backend that records each LogEntry into a
+//MergedRecorder tagged with the name of the Logger it was added to.
+type recorderBackend struct {
+	recorder *MergedRecorder
+	name     string
+}
+
+//Log satisfies the Backend interfaces requirements used for accepting
+//LogEntry objects to record.
+func (b *recorderBackend) Log(entry *LogEntry) {
+	b.recorder.Lock()
+	defer b.recorder.Unlock()
+	b.recorder.entries = append(b.recorder.entries, RecordedEntry{Logger: b.name, Entry: *entry})
+}
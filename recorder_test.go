@@ -0,0 +1,60 @@
+package lumberjack
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestMergedRecorder(t *testing.T) {
+	recorder := NewMergedRecorder()
+
+	api := NewLogger()
+	api.AddLevel(INFO)
+	api.AddBackend("recorder", recorder.Backend("api"))
+
+	db := NewLogger()
+	db.AddLevel(ERROR)
+	db.AddBackend("recorder", recorder.Backend("db"))
+
+	api.Info("request received")
+	db.Error("query failed")
+	api.Info("request failed")
+
+	entries := recorder.Entries()
+	expect(t, len(entries), 3)
+	expect(t, entries[0].Logger, "api")
+	expect(t, entries[0].Entry.Message, "request received")
+	expect(t, entries[1].Logger, "db")
+	expect(t, entries[1].Entry.Level, ERROR)
+	expect(t, entries[2].Logger, "api")
+	expect(t, entries[2].Entry.Message, "request failed")
+
+	recorder.Reset()
+	expect(t, len(recorder.Entries()), 0)
+}
+
+func TestMergedRecorderConcurrent(t *testing.T) {
+	recorder := NewMergedRecorder()
+
+	var wg sync.WaitGroup
+	for _, name := range []string{"one", "two", "three"} {
+		logger := NewLogger()
+		logger.AddLevel(INFO)
+		logger.AddBackend("recorder", recorder.Backend(name))
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				logger.Info("entry")
+			}
+		}()
+	}
+	wg.Wait()
+
+	counts := map[string]int{}
+	for _, entry := range recorder.Entries() {
+		counts[entry.Logger]++
+	}
+	expect(t, counts, map[string]int{"one": 100, "two": 100, "three": 100})
+}
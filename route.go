@@ -0,0 +1,65 @@
+package lumberjack
+
+import "fmt"
+
+//route is a routing rule sending the LogEntry objects matching a predicate
+//to a particular Backend.
+type route struct {
+	predicate func(*LogEntry) bool
+	backend   string
+	exclusive bool
+}
+
+//Route adds a routing rule to the current Logger that sends every LogEntry
+//matching the specified predicate to the Backend added with the specified
+//name. Once routed to, a Backend only receives the LogEntry objects matching
+//its routing rules, rather than every LogEntry. If exclusive is true, a
+//matching LogEntry is sent only to routed Backends and skips every other
+//Backend, such as to divert audit entries to a secure store.
+//
+//The predicate is called while the Logger is locked, so it must not log.
+func (l *Logger) Route(predicate func(*LogEntry) bool, backendName string, exclusive bool) error {
+	l.Lock()
+	defer l.Unlock()
+
+	if _, exists := l.backends[backendName]; !exists {
+		return fmt.Errorf("Backend with that name does not exist: %s", backendName)
+	}
+
+	l.routes = append(l.routes, route{predicate: predicate, backend: backendName, exclusive: exclusive})
+	return nil
+}
+
+//ClearRoutes removes every routing rule from the current Logger, so that
+//every Backend receives every LogEntry again.
+func (l *Logger) ClearRoutes() {
+	l.Lock()
+	defer l.Unlock()
+	l.routes = nil
+}
+
+//routeEntry applies the routing rules of the current Logger to the specified
+//LogEntry, returning whether the Backend with the specified name should
+//receive it. The caller must hold the lock.
+func (l *Logger) routeEntry(entry *LogEntry) func(name string) bool {
+	if len(l.routes) == 0 {
+		return func(string) bool { return true }
+	}
+
+	routed := map[string]bool{}
+	exclusive := false
+	for _, r := range l.routes {
+		matched := r.predicate(entry)
+		routed[r.backend] = routed[r.backend] || matched
+		if matched && r.exclusive {
+			exclusive = true
+		}
+	}
+
+	return func(name string) bool {
+		if matched, isRouted := routed[name]; isRouted {
+			return matched
+		}
+		return !exclusive
+	}
+}
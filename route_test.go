@@ -0,0 +1,60 @@
+package lumberjack
+
+import "testing"
+
+//isAudit is a routing predicate matching LogEntry objects tagged as audit.
+func isAudit(entry *LogEntry) bool {
+	return entry.Fields["audit"] == true
+}
+
+func TestRouteExclusive(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+
+	secure := &captureBackend{}
+	normal := &captureBackend{}
+	logger.AddBackend("secure", secure)
+	logger.AddBackend("normal", normal)
+
+	if err := logger.Route(isAudit, "secure", true); err != nil {
+		t.Fatal(err)
+	}
+
+	logger.Info("regular")
+	logger.ErrorErr(nil, "not enabled", "audit", true)
+	logger.AddLevel(ERROR)
+	logger.ErrorErr(nil, "user deleted", "audit", true)
+
+	expect(t, len(secure.Entries()), 1)
+	expect(t, secure.Entries()[0].Message, "user deleted")
+	expect(t, len(normal.Entries()), 1)
+	expect(t, normal.Entries()[0].Message, "regular")
+}
+
+func TestRouteNonExclusive(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(ERROR)
+
+	secure := &captureBackend{}
+	normal := &captureBackend{}
+	logger.AddBackend("secure", secure)
+	logger.AddBackend("normal", normal)
+
+	if err := logger.Route(isAudit, "secure", false); err != nil {
+		t.Fatal(err)
+	}
+
+	logger.Error("regular")
+	logger.ErrorErr(nil, "user deleted", "audit", true)
+
+	expect(t, len(secure.Entries()), 1)
+	expect(t, len(normal.Entries()), 2)
+
+	logger.ClearRoutes()
+	logger.Error("after clearing")
+	expect(t, len(secure.Entries()), 2)
+
+	if err := logger.Route(isAudit, "missing", false); err == nil {
+		t.Error("Expected error routing to a Backend that does not exist")
+	}
+}
@@ -0,0 +1,112 @@
+package lumberjack
+
+import (
+	"fmt"
+	"math/rand"
+	"sync/atomic"
+	"time"
+)
+
+//SetSampleRate sets the fraction of TRACE, DEBUG, and INFO LogEntry objects
+//the current Logger keeps, chosen at random, to reduce the volume of high
+//frequency logs. WARN and more severe LogEntry objects are never sampled,
+//unless another threshold is set with SetSampleThreshold. The rate must be
+//greater than 0 and at most 1, where 1 keeps every LogEntry.
+func (l *Logger) SetSampleRate(rate float64) error {
+	if rate <= 0 || rate > 1 {
+		return fmt.Errorf("Sample rate must be greater than 0 and at most 1: %v", rate)
+	}
+	l.Lock()
+	defer l.Unlock()
+	l.sampleRate = rate
+	return nil
+}
+
+//counterSampler keeps the first of every so many LogEntry objects it sees.
+type counterSampler struct {
+	count uint64 //First for 64-bit alignment of its atomic counter.
+	every uint64
+}
+
+//keep counts a LogEntry, returning true if it is one to be kept.
+func (s *counterSampler) keep() bool {
+	return (atomic.AddUint64(&s.count, 1)-1)%s.every == 0
+}
+
+//SetSampler sets the current Logger to keep only 1 of every n TRACE, DEBUG,
+//and INFO LogEntry objects, such as those logged in a hot loop, to reduce the
+//volume of high frequency logs predictably. WARN and more severe LogEntry
+//objects are never sampled, unless another threshold is set with
+//SetSampleThreshold. If a sample rate is also set with SetSampleRate, both
+//apply. An n of 1 keeps every LogEntry, and n must be at least 1.
+func (l *Logger) SetSampler(n int) error {
+	if n < 1 {
+		return fmt.Errorf("Sampler interval must be at least 1: %d", n)
+	}
+	l.Lock()
+	defer l.Unlock()
+	l.sampler = nil
+	if n > 1 {
+		l.sampler = &counterSampler{every: uint64(n)}
+	}
+	return nil
+}
+
+//SetSampleThreshold sets the LogLevel at and above which LogEntry objects are
+//never sampled by the current Logger, being WARN by default.
+func (l *Logger) SetSampleThreshold(level LogLevel) error {
+	if !validLevel(level) {
+		return fmt.Errorf("Invalid LogLevel: %d", level)
+	}
+	l.Lock()
+	defer l.Unlock()
+	l.sampleThreshold = level
+	return nil
+}
+
+//SetRandSource sets the source of randomness used by the current Logger for
+//sampling, such as a rand.Source with a fixed seed so that tests can assert
+//exact sampling decisions. By default, a source seeded with the current time
+//is used.
+func (l *Logger) SetRandSource(source rand.Source) {
+	l.Lock()
+	defer l.Unlock()
+	l.rng = rand.New(source)
+}
+
+//sampledOut returns true if the specified LogEntry is dropped by sampling.
+//A LogEntry kept by sampling is marked as Sampled along with the SampleRate it
+//was kept at, so that downstream systems can extrapolate the true volume.
+func (l *Logger) sampledOut(entry *LogEntry) bool {
+	l.Lock()
+	defer l.Unlock()
+
+	if entry.Level >= l.sampleThreshold {
+		return false
+	}
+
+	rate := 1.0
+
+	if l.sampler != nil {
+		if !l.sampler.keep() {
+			return true
+		}
+		rate /= float64(l.sampler.every)
+	}
+
+	if l.sampleRate > 0 && l.sampleRate < 1 {
+		if l.rng == nil {
+			l.rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+		}
+		if l.rng.Float64() >= l.sampleRate {
+			return true
+		}
+		rate *= l.sampleRate
+	}
+
+	if rate < 1 {
+		entry.Sampled = true
+		entry.SampleRate = rate
+	}
+	return false
+}
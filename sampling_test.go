@@ -0,0 +1,171 @@
+package lumberjack
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+//sampledMessages logs the messages "0" to "19" at the specified LogLevel
+//through a Logger sampling at half rate with a fixed seed, returning the
+//messages that were kept.
+func sampledMessages(t *testing.T, level LogLevel) []string {
+	logger := NewLogger()
+	logger.AddLevel(level)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	if err := logger.SetSampleRate(0.5); err != nil {
+		t.Fatal(err)
+	}
+	logger.SetRandSource(rand.NewSource(42))
+
+	for i := 0; i < 20; i++ {
+		logger.Errorf("%d", i) //Only logged if ERROR is the level under test.
+		logger.Infof("%d", i)
+	}
+
+	var kept []string
+	for _, entry := range capture.Entries() {
+		kept = append(kept, entry.Message)
+	}
+	return kept
+}
+
+func TestSampleRateDeterministic(t *testing.T) {
+	first := sampledMessages(t, INFO)
+	second := sampledMessages(t, INFO)
+
+	//The same seed always makes the same decisions.
+	expect(t, first, second)
+
+	//Replay the source to assert the exact pattern.
+	rng := rand.New(rand.NewSource(42))
+	var want []string
+	for i := 0; i < 20; i++ {
+		if rng.Float64() < 0.5 {
+			want = append(want, strconv.Itoa(i))
+		}
+	}
+	expect(t, first, want)
+}
+
+func TestSampleRateSkipsSevereLevels(t *testing.T) {
+	expect(t, len(sampledMessages(t, ERROR)), 20)
+}
+
+func TestSampleRateInvalid(t *testing.T) {
+	logger := NewLogger()
+	for _, rate := range []float64{0, -1, 1.5} {
+		if err := logger.SetSampleRate(rate); err == nil {
+			t.Errorf("Expected error for sample rate %v", rate)
+		}
+	}
+}
+
+func TestSampleRateMarksEntries(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+	logger.AddLevel(ERROR)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	if err := logger.SetSampleRate(0.25); err != nil {
+		t.Fatal(err)
+	}
+	logger.SetRandSource(rand.NewSource(7))
+
+	for i := 0; i < 40; i++ {
+		logger.Infof("%d", i)
+	}
+	logger.Error("never sampled")
+
+	entries := capture.Entries()
+	if len(entries) < 2 || len(entries) > 40 {
+		t.Fatalf("Unexpected number of entries kept: %d", len(entries))
+	}
+
+	// Kept entries carry the rate, dropped ones never reach the backend.
+	info := entries[:len(entries)-1]
+	expect(t, len(info) < 40, true)
+	for _, entry := range info {
+		expect(t, entry.Sampled, true)
+		expect(t, entry.SampleRate, 0.25)
+	}
+
+	// Entries exempt from sampling aren't marked.
+	last := entries[len(entries)-1]
+	expect(t, last.Message, "never sampled")
+	expect(t, last.Sampled, false)
+	expect(t, last.SampleRate, float64(0))
+}
+
+func TestSetSampler(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+	logger.AddLevel(ERROR)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	if err := logger.SetSampler(10); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 1000; i++ {
+		logger.Infof("%d", i)
+		logger.Errorf("%d", i)
+	}
+
+	var infos, errors int
+	for _, entry := range capture.Entries() {
+		switch entry.Level {
+		case INFO:
+			infos++
+			expect(t, entry.Sampled, true)
+			expect(t, entry.SampleRate, 0.1)
+		case ERROR:
+			errors++
+			expect(t, entry.Sampled, false)
+		}
+	}
+	expect(t, infos, 100)
+	expect(t, errors, 1000)
+
+	for _, n := range []int{0, -1} {
+		if err := logger.SetSampler(n); err == nil {
+			t.Errorf("Expected error for sampler interval %d", n)
+		}
+	}
+}
+
+func TestSetSampleThreshold(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(WARN)
+	logger.AddLevel(ERROR)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	logger.SetSampler(2)
+	if err := logger.SetSampleThreshold(ERROR); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		logger.Warn("sampled")
+		logger.Error("kept")
+	}
+
+	counts := map[LogLevel]int{}
+	for _, entry := range capture.Entries() {
+		counts[entry.Level]++
+	}
+	expect(t, counts, map[LogLevel]int{WARN: 5, ERROR: 10})
+
+	if err := logger.SetSampleThreshold(LogLevel(99)); err == nil {
+		t.Error("Expected error for an invalid LogLevel")
+	}
+}
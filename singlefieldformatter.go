@@ -0,0 +1,56 @@
+package lumberjack
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//SingleFieldFormatter implements a Formatter that renders each LogEntry as
+//JSON with a single key, holding the whole LogEntry rendered as a line of
+//text, with the LogLevel, caller, and message in the PrintBackend format
+//determined by Verbosity, followed by the structured Fields as key=value
+//pairs. This suits log ingestion setups that only keep a single message key.
+//The key defaults to "msg" if Key is not specified.
+type SingleFieldFormatter struct {
+	Key       string
+	Verbosity LogLevel
+}
+
+//Format satisfies the Formatter interface's requirements used for rendering
+//a LogEntry into JSON with a single key.
+func (f *SingleFieldFormatter) Format(entry *LogEntry) ([]byte, error) {
+	key := f.Key
+	if key == "" {
+		key = "msg"
+	}
+
+	line := formatEntry(f.Verbosity, entry.Level.String(), entry) + formatFields(entry.Fields)
+	return json.Marshal(map[string]string{key: line})
+}
+
+//formatFields renders the specified structured fields as key=value pairs
+//sorted by key, each preceded by a space. Values containing whitespace,
+//quotes, or equals signs are quoted.
+func formatFields(fields map[string]interface{}) string {
+	if len(fields) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, key := range keys {
+		value := fmt.Sprintf("%v", fields[key])
+		if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+			value = fmt.Sprintf("%q", value)
+		}
+		fmt.Fprintf(&b, " %s=%s", key, value)
+	}
+	return b.String()
+}
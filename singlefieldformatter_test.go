@@ -0,0 +1,38 @@
+package lumberjack
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestSingleFieldFormatter(t *testing.T) {
+	var buf bytes.Buffer
+	backend := NewJSONBackend(&buf)
+	backend.Formatter = &SingleFieldFormatter{Verbosity: ERROR}
+
+	entry := testobj.Entries[0]
+	entry.SetField("user", "bob smith")
+	entry.SetField("attempt", 2)
+	backend.Log(&entry)
+
+	out := map[string]interface{}{}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	expect(t, out, map[string]interface{}{
+		"msg": `(ERROR) @ main.main()() main.go:10: Test Error attempt=2 user="bob smith"`,
+	})
+}
+
+func TestSingleFieldFormatterKey(t *testing.T) {
+	entry := testobj.Entries[1]
+
+	data, err := (&SingleFieldFormatter{Key: "log", Verbosity: ERROR}).Format(&entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect(t, string(data), `{"log":"(INFO) @ main.main()(): Test Info"}`)
+}
@@ -0,0 +1,53 @@
+package lumberjack
+
+//SizeStats holds the accumulated statistics about the byte sizes of the
+//log messages sent by a Logger. It can be used to spot code paths that
+//emit excessively large log messages.
+type SizeStats struct {
+	Count      uint64 `json:"count"`
+	TotalBytes uint64 `json:"total_bytes"`
+	MaxBytes   int    `json:"max_bytes"`
+}
+
+//EnableSizeStats turns on the tracking of log message sizes for the current
+//Logger, resetting any previously accumulated statistics. Tracking is off by
+//default to avoid the overhead when unused.
+func (l *Logger) EnableSizeStats() {
+	l.Lock()
+	defer l.Unlock()
+	l.sizeStats = &SizeStats{}
+}
+
+//DisableSizeStats turns off the tracking of log message sizes for the
+//current Logger and discards any accumulated statistics.
+func (l *Logger) DisableSizeStats() {
+	l.Lock()
+	defer l.Unlock()
+	l.sizeStats = nil
+}
+
+//SizeStats returns a copy of the log message size statistics accumulated
+//by the current Logger. The zero value is returned if tracking is disabled.
+func (l *Logger) SizeStats() SizeStats {
+	l.Lock()
+	defer l.Unlock()
+	if l.sizeStats == nil {
+		return SizeStats{}
+	}
+	return *l.sizeStats
+}
+
+//recordSize adds the byte size of a log message to the statistics of the
+//current Logger if tracking is enabled.
+func (l *Logger) recordSize(size int) {
+	l.Lock()
+	defer l.Unlock()
+	if l.sizeStats == nil {
+		return
+	}
+	l.sizeStats.Count++
+	l.sizeStats.TotalBytes += uint64(size)
+	if size > l.sizeStats.MaxBytes {
+		l.sizeStats.MaxBytes = size
+	}
+}
@@ -0,0 +1,28 @@
+package lumberjack
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSizeStats(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+	logger.AddBackend("capture", &captureBackend{})
+
+	// Nothing is tracked until enabled.
+	logger.Info("untracked")
+	expect(t, logger.SizeStats(), SizeStats{})
+
+	logger.EnableSizeStats()
+
+	logger.Info(strings.Repeat("a", 10))
+	logger.Info(strings.Repeat("b", 100))
+	logger.Info(strings.Repeat("c", 40))
+
+	expect(t, logger.SizeStats(), SizeStats{Count: 3, TotalBytes: 150, MaxBytes: 100})
+
+	logger.DisableSizeStats()
+	logger.Info("untracked")
+	expect(t, logger.SizeStats(), SizeStats{})
+}
@@ -0,0 +1,134 @@
+//go:build go1.21
+// +build go1.21
+
+package lumberjack
+
+import (
+	"context"
+	"log/slog"
+)
+
+//SlogHandler implements a slog.Handler that sends each slog.Record to the
+//backends of a Logger as a LogEntry, so that code logging with the standard
+//library log/slog package is routed through the Logger. The attributes of each
+//slog.Record, along with those added with WithAttrs, are set as structured
+//fields, with the keys of attributes within a group prefixed with the group
+//name and a dot, such as "request.method".
+type SlogHandler struct {
+	logger *Logger
+	fields map[string]interface{}
+	prefix string
+}
+
+//NewSlogHandler returns an instance of SlogHandler that sends each slog.Record
+//to the backends of the specified Logger, to be passed to slog.New.
+func NewSlogHandler(logger *Logger) *SlogHandler {
+	return &SlogHandler{logger: logger}
+}
+
+//Enabled satisfies the slog.Handler interface, reporting whether the LogLevel
+//the specified slog.Level maps to is added to the Logger, and there are
+//backends to receive it.
+func (h *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	_, ok := h.logger.begin(slogLevel(level))
+	return ok
+}
+
+//Handle satisfies the slog.Handler interface, sending the specified
+//slog.Record to the backends of the Logger as a LogEntry, with the source
+//information of the call to log recorded by slog and the specified
+//context.Context attached.
+func (h *SlogHandler) Handle(ctx context.Context, record slog.Record) error {
+	entry := callSiteForPC(record.PC).entry(slogLevel(record.Level), record.Message)
+	if !record.Time.IsZero() {
+		entry.Time = record.Time
+	}
+	if ctx != nil && ctx != context.Background() {
+		entry.Context = ctx
+	}
+
+	fields := make(map[string]interface{}, len(h.fields)+record.NumAttrs())
+	for key, value := range h.fields {
+		fields[key] = value
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		setSlogAttr(fields, h.prefix, attr)
+		return true
+	})
+	for key, value := range fields {
+		entry.SetField(key, value)
+	}
+
+	h.logger.dispatch(entry)
+	return nil
+}
+
+//WithAttrs satisfies the slog.Handler interface, returning a SlogHandler that
+//sets the specified attributes on every LogEntry, within the current group.
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	child := *h
+	child.fields = make(map[string]interface{}, len(h.fields)+len(attrs))
+	for key, value := range h.fields {
+		child.fields[key] = value
+	}
+	for _, attr := range attrs {
+		setSlogAttr(child.fields, h.prefix, attr)
+	}
+	return &child
+}
+
+//WithGroup satisfies the slog.Handler interface, returning a SlogHandler that
+//prefixes the keys of the attributes added after it with the specified group
+//name and a dot.
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	child := *h
+	child.prefix = h.prefix + name + "."
+	return &child
+}
+
+//setSlogAttr is an internal function that sets the specified attribute with
+//its key prefixed with the specified prefix on the specified fields. The
+//attributes of a group are set individually, prefixed with the group name.
+func setSlogAttr(fields map[string]interface{}, prefix string, attr slog.Attr) {
+	value := attr.Value.Resolve()
+
+	if value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, member := range value.Group() {
+			setSlogAttr(fields, prefix, member)
+		}
+		return
+	}
+
+	if attr.Key == "" {
+		return //Ignored, as with the handlers of the slog package.
+	}
+
+	fields[prefix+attr.Key] = value.Any()
+}
+
+//slogLevel is an internal function that maps the specified slog.Level to the
+//LogLevel with the same severity, with levels between those defined by slog
+//mapped to the less severe LogLevel. Levels below slog.LevelDebug map to
+//TRACE, and levels well above slog.LevelError map to CRITICAL.
+func slogLevel(level slog.Level) LogLevel {
+	switch {
+	case level < slog.LevelDebug:
+		return TRACE
+	case level < slog.LevelInfo:
+		return DEBUG
+	case level < slog.LevelWarn:
+		return INFO
+	case level < slog.LevelError:
+		return WARN
+	case level < slog.LevelError+4:
+		return ERROR
+	default:
+		return CRITICAL
+	}
+}
@@ -0,0 +1,87 @@
+//go:build go1.21
+// +build go1.21
+
+package lumberjack
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+)
+
+func TestSlogHandlerLevels(t *testing.T) {
+	tests := []struct {
+		level slog.Level
+		want  LogLevel
+	}{
+		{slog.LevelDebug - 4, TRACE},
+		{slog.LevelDebug, DEBUG},
+		{slog.LevelInfo, INFO},
+		{slog.LevelInfo + 2, INFO},
+		{slog.LevelWarn, WARN},
+		{slog.LevelError, ERROR},
+		{slog.LevelError + 4, CRITICAL},
+	}
+
+	for _, test := range tests {
+		expect(t, slogLevel(test.level), test.want)
+	}
+
+	logger := NewLogger()
+	logger.AddLevel(WARN)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	log := slog.New(NewSlogHandler(logger))
+	log.Info("hidden")
+	log.Warn("shown")
+
+	entries := capture.Entries()
+	expect(t, len(entries), 1)
+	expect(t, entries[0].Level, WARN)
+	expect(t, entries[0].Message, "shown")
+	expect(t, entries[0].File, "slog_test.go")
+}
+
+func TestSlogHandlerAttrs(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	log := slog.New(NewSlogHandler(logger)).With("service", "billing")
+	request := log.WithGroup("request").With("method", "GET")
+
+	ctx := context.WithValue(context.Background(), spanKey{}, "span")
+	request.InfoContext(ctx, "handled",
+		"status", 200,
+		slog.Group("user", "id", 42, "admin", false),
+	)
+	log.Info("plain", "", "ignored")
+
+	entries := capture.Entries()
+	expect(t, len(entries), 2)
+	expect(t, entries[0].Fields, map[string]interface{}{
+		"service":            "billing",
+		"request.method":     "GET",
+		"request.status":     int64(200),
+		"request.user.id":    int64(42),
+		"request.user.admin": false,
+	})
+	expect(t, entries[0].Context, ctx)
+	expect(t, entries[1].Fields, map[string]interface{}{"service": "billing"})
+}
+
+func TestSlogHandlerEnabledNoBackends(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+	logger.SuppressNoBackendWarning()
+
+	handler := NewSlogHandler(logger)
+	expect(t, handler.Enabled(context.Background(), slog.LevelInfo), false)
+
+	logger.AddBackend("discard", DiscardBackend{})
+	expect(t, handler.Enabled(context.Background(), slog.LevelInfo), true)
+}
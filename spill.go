@@ -0,0 +1,90 @@
+package lumberjack
+
+import "sync/atomic"
+
+//SpillStats holds the statistics of the spill buffer of a network backend,
+//with the total number of LogEntry objects spilled during outages, dropped
+//because the spill buffer was full, and replayed after recovering, along
+//with the number currently waiting in the spill buffer.
+type SpillStats struct {
+	Spilled  uint64 `json:"spilled"`
+	Dropped  uint64 `json:"dropped"`
+	Replayed uint64 `json:"replayed"`
+	Pending  uint64 `json:"pending"`
+}
+
+//spillBuffer is a bounded in-memory queue of LogEntry objects that a network
+//backend failed to send, held to be replayed oldest-first once the backend
+//recovers. When full, the oldest LogEntry objects are dropped to make room.
+//
+//The queue itself must only be used by a single Goroutine, while the
+//statistics may be read from any Goroutine.
+type spillBuffer struct {
+	queue    []LogEntry
+	capacity int
+	spilled  uint64
+	dropped  uint64
+	replayed uint64
+	pending  uint64
+}
+
+//newSpillBuffer returns a spillBuffer that holds at most the specified
+//number of LogEntry objects.
+func newSpillBuffer(capacity int) *spillBuffer {
+	return &spillBuffer{capacity: capacity}
+}
+
+//add appends copies of the specified LogEntry objects to the spillBuffer,
+//dropping the oldest if the capacity is exceeded.
+func (s *spillBuffer) add(entries []LogEntry) {
+	s.queue = append(s.queue, entries...)
+	atomic.AddUint64(&s.spilled, uint64(len(entries)))
+
+	if over := len(s.queue) - s.capacity; over > 0 {
+		s.queue = append([]LogEntry(nil), s.queue[over:]...)
+		atomic.AddUint64(&s.dropped, uint64(over))
+	}
+
+	atomic.StoreUint64(&s.pending, uint64(len(s.queue)))
+}
+
+//entries returns the LogEntry objects waiting in the spillBuffer,
+//oldest first.
+func (s *spillBuffer) entries() []LogEntry {
+	return s.queue
+}
+
+//replay empties the spillBuffer once its LogEntry objects have been sent.
+func (s *spillBuffer) replay() {
+	s.replayN(len(s.queue))
+}
+
+//replayN removes the specified number of the oldest LogEntry objects from the
+//spillBuffer once they have been sent, such as by a backend sending them one
+//at a time.
+func (s *spillBuffer) replayN(n int) {
+	atomic.AddUint64(&s.replayed, uint64(n))
+	s.queue = s.queue[n:]
+	if len(s.queue) == 0 {
+		s.queue = nil
+	}
+	atomic.StoreUint64(&s.pending, uint64(len(s.queue)))
+}
+
+//discard empties the spillBuffer, counting its LogEntry objects as dropped,
+//such as when a backend is closed before they could be sent.
+func (s *spillBuffer) discard() {
+	atomic.AddUint64(&s.dropped, uint64(len(s.queue)))
+	s.queue = nil
+	atomic.StoreUint64(&s.pending, 0)
+}
+
+//stats returns the statistics of the spillBuffer.
+func (s *spillBuffer) stats() SpillStats {
+	return SpillStats{
+		Spilled:  atomic.LoadUint64(&s.spilled),
+		Dropped:  atomic.LoadUint64(&s.dropped),
+		Replayed: atomic.LoadUint64(&s.replayed),
+		Pending:  atomic.LoadUint64(&s.pending),
+	}
+}
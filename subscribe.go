@@ -0,0 +1,55 @@
+package lumberjack
+
+import (
+	"fmt"
+	"sync"
+)
+
+//subscriberBackend implements a Backend that sends a copy of each LogEntry
+//to a channel consumed by a subscriber. If the channel is full, the LogEntry
+//is dropped for that subscriber so that a slow consumer can't block logging.
+type subscriberBackend struct {
+	entries chan LogEntry
+}
+
+//Log satisfies the Backend interfaces requirements used for accepting
+//LogEntry objects to send to the subscriber.
+func (b *subscriberBackend) Log(entry *LogEntry) {
+	select {
+	case b.entries <- *entry:
+	default: //Full, drop it for this subscriber.
+	}
+}
+
+//Subscribe returns a channel that receives a copy of every LogEntry sent to
+//the backends of the current Logger, holding up to the specified buffer
+//LogEntry objects, along with a function to unsubscribe that closes the
+//channel. Once the buffer is full, further LogEntry objects are dropped for
+//the subscriber until it catches up, rather than blocking logging.
+func (l *Logger) Subscribe(buffer int) (<-chan LogEntry, func()) {
+	b := &subscriberBackend{entries: make(chan LogEntry, buffer)}
+
+	l.Lock()
+	l.subscriptions++
+	name := fmt.Sprintf("%ssubscriber.%d", reservedBackendPrefix, l.subscriptions)
+	l.backends[name] = b
+	l.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			//Wait for any LogEntry being sent to the subscriber, as backends
+			//are called outside the lock, so none is sent once it is closed.
+			l.inflight.Lock()
+			defer l.inflight.Unlock()
+
+			l.Lock()
+			delete(l.backends, name)
+			l.Unlock()
+
+			close(b.entries)
+		})
+	}
+
+	return b.entries, unsubscribe
+}
@@ -0,0 +1,73 @@
+package lumberjack
+
+import "testing"
+
+func TestSubscribe(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+
+	entries, unsubscribe := logger.Subscribe(10)
+
+	logger.Info("one")
+	logger.Info("two")
+
+	expect(t, (<-entries).Message, "one")
+	expect(t, (<-entries).Message, "two")
+
+	unsubscribe()
+	unsubscribe() //Unsubscribing twice has no effect.
+	logger.Info("three")
+
+	_, open := <-entries
+	expect(t, open, false)
+	expect(t, len(logger.backends), 0)
+}
+
+func TestSubscribeFullBufferDrops(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	entries, unsubscribe := logger.Subscribe(2)
+	defer unsubscribe()
+
+	//Nothing is consuming, so this would block if the full buffer didn't drop.
+	for i := 0; i < 5; i++ {
+		logger.Info("entry")
+	}
+
+	expect(t, len(entries), 2)
+	expect(t, len(capture.Entries()), 5)
+}
+
+func TestUnsubscribeWhileLogging(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+	logger.AddBackend("discard", DiscardBackend{})
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				logger.Info("entry")
+			}
+		}
+	}()
+
+	//Would panic sending on a closed channel if unsubscribing didn't wait for
+	//a LogEntry being sent outside the lock.
+	for i := 0; i < 200; i++ {
+		_, unsubscribe := logger.Subscribe(1)
+		unsubscribe()
+	}
+
+	close(stop)
+	<-done
+}
@@ -0,0 +1,139 @@
+package lumberjack
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+//Summary holds the statistics of the logging done by a Logger over its
+//lifetime, with the number of LogEntry objects logged per LogLevel, the
+//total bytes of the logged messages, and the number of LogEntry objects
+//dropped by backends, both those implementing the Dropper interface and
+//those dropped from the spill buffer of backends that track spill statistics.
+type Summary struct {
+	Counts  map[LogLevel]uint64 `json:"counts"`
+	Bytes   uint64              `json:"bytes"`
+	Dropped uint64              `json:"dropped"`
+}
+
+//spillStatser is implemented by backends that track the statistics of
+//their spill buffer, such as the HttpClientBackend.
+type spillStatser interface {
+	SpillStats() SpillStats
+}
+
+//EnableCloseSummary turns on the logging of a final INFO LogEntry holding
+//the Summary of the current Logger when Close is called, so that the log
+//ends by documenting the logging health of the run. It is off by default.
+func (l *Logger) EnableCloseSummary() {
+	l.Lock()
+	defer l.Unlock()
+	l.closeSummary = true
+}
+
+//DisableCloseSummary turns off the logging of the Summary when Close is called.
+func (l *Logger) DisableCloseSummary() {
+	l.Lock()
+	defer l.Unlock()
+	l.closeSummary = false
+}
+
+//Summary returns the Summary of the logging done by the current Logger so far.
+func (l *Logger) Summary() Summary {
+	l.Lock()
+	defer l.Unlock()
+	return l.summary()
+}
+
+//summary is an internal method that builds the Summary of the current
+//Logger. The caller must hold the lock.
+func (l *Logger) summary() Summary {
+	summary := Summary{
+		Counts: map[LogLevel]uint64{},
+		Bytes:  l.loggedBytes,
+	}
+
+	for level, count := range l.counts {
+		summary.Counts[level] = count
+	}
+
+	for _, backend := range l.backends {
+		if s, ok := backend.(spillStatser); ok {
+			summary.Dropped += s.SpillStats().Dropped
+		}
+		if d, ok := backend.(Dropper); ok {
+			summary.Dropped += d.Dropped()
+		}
+	}
+
+	return summary
+}
+
+//Close logs the Summary of the current Logger if enabled with
+//EnableCloseSummary, then flushes each backend that implements the Flusher
+//interface and closes each backend that implements io.Closer, such as the
+//FileBackend and HttpClientBackend, replacing closing each of them by hand.
+//The Summary is returned along with an error listing every backend that
+//failed to flush or close, if any. Calling Close more than once only returns
+//the Summary, the backends are closed just the once.
+func (l *Logger) Close() (Summary, error) {
+	l.Lock()
+	summary := l.summary()
+	emit := l.closeSummary && !l.closed
+	alreadyClosed := l.closed
+	l.closed = true
+	l.Unlock()
+
+	if alreadyClosed {
+		return summary, nil
+	}
+
+	if emit {
+		l.logSummary(summary)
+	}
+
+	var failures []string
+	if err := l.flushBackends(); err != nil {
+		failures = append(failures, err.Error())
+	}
+
+	l.Lock()
+	names := make([]string, 0, len(l.backends))
+	backends := make(map[string]Backend, len(l.backends))
+	for name, backend := range l.backends {
+		names = append(names, name)
+		backends[name] = backend
+	}
+	l.Unlock()
+
+	sort.Strings(names)
+	for _, name := range names {
+		if closer, ok := backends[name].(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %s", name, err))
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		return summary, fmt.Errorf("Unable to close Logger: %s", strings.Join(failures, "; "))
+	}
+	return summary, nil
+}
+
+//logSummary logs a LogEntry holding the specified Summary, with the source
+//information of the caller of the method calling it.
+func (l *Logger) logSummary(summary Summary) {
+	counts := map[string]uint64{}
+	for level, count := range summary.Counts {
+		counts[level.String()] = count
+	}
+
+	entry := captureCallSite(3).entry(INFO, "Logging summary")
+	entry.SetField("counts", counts)
+	entry.SetField("bytes", summary.Bytes)
+	entry.SetField("dropped", summary.Dropped)
+	l.dispatch(entry)
+}
@@ -0,0 +1,154 @@
+package lumberjack
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCloseSummary(t *testing.T) {
+	logger := NewLogger()
+	for _, level := range []LogLevel{INFO, WARN, ERROR, DEBUG} {
+		logger.AddLevel(level)
+	}
+
+	capture := &captureBackend{}
+	buffer := &bufferBackend{}
+	logger.AddBackend("capture", capture)
+	logger.AddBackend("buffer", buffer)
+	logger.EnableCloseSummary()
+
+	logger.Info("one")
+	logger.Info("two")
+	logger.Warn("three")
+	logger.Error("four")
+	logger.Debug("five")
+	logger.Critical("dropped") //CRITICAL isn't added, so it isn't counted.
+
+	summary, err := logger.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect(t, summary.Counts, map[LogLevel]uint64{INFO: 2, WARN: 1, ERROR: 1, DEBUG: 1})
+	expect(t, summary.Bytes, uint64(len("onetwothreefourfive")))
+	expect(t, summary.Dropped, uint64(0))
+
+	entries := capture.Entries()
+	expect(t, len(entries), 6)
+
+	last := entries[len(entries)-1]
+	expect(t, last.Level, INFO)
+	expect(t, last.Message, "Logging summary")
+	expect(t, last.Fields["counts"], map[string]uint64{"INFO": 2, "WARN": 1, "ERROR": 1, "DEBUG": 1})
+	expect(t, last.Fields["bytes"], summary.Bytes)
+
+	//The summary is flushed and delivered before the backends are closed.
+	expect(t, buffer.closed, true)
+	expect(t, len(buffer.Delivered()), 6)
+}
+
+func TestCloseWithoutSummary(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	logger.Info("one")
+
+	summary, err := logger.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect(t, summary.Counts, map[LogLevel]uint64{INFO: 1})
+	expect(t, len(capture.Entries()), 1)
+}
+
+func TestCloseBackends(t *testing.T) {
+	path, cleanup := tempLogPath(t)
+	defer cleanup()
+
+	server, count := countingServer(t)
+	defer server.Close()
+
+	fb, err := NewFileBackend(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hb := NewHttpClientBackend(server.URL, 100, time.Hour)
+
+	logger := NewLogger()
+	logger.AddLevel(ERROR)
+	logger.AddBackend("file", fb)
+	logger.AddBackend("http", hb)
+
+	logger.Error("one")
+	logger.Error("two")
+
+	if _, err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	//Both backends delivered what was logged, then were torn down.
+	expect(t, len(readLines(t, path)), 2)
+	expect(t, count(), 2)
+	if err := fb.Rotate(); err == nil {
+		t.Error("Expected the file backend to be closed")
+	}
+	if err := hb.Flush(); err == nil {
+		t.Error("Expected the HTTP backend to be stopped")
+	}
+
+	//Closing again is safe.
+	if _, err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+//failingCloser is a Backend that fails to close.
+type failingCloser struct {
+	DiscardBackend
+}
+
+func (failingCloser) Close() error {
+	return errors.New("disk on fire")
+}
+
+func TestCloseCollectsErrors(t *testing.T) {
+	logger := NewLogger()
+	logger.AddBackend("first", failingCloser{})
+	logger.AddBackend("second", failingCloser{})
+	logger.AddBackend("fine", &bufferBackend{})
+
+	_, err := logger.Close()
+	if err == nil {
+		t.Fatal("Expected an error closing failing backends")
+	}
+	expect(t, err.Error(), "Unable to close Logger: first: disk on fire; second: disk on fire")
+}
+
+//droppingBackend is a Backend reporting a fixed number of dropped LogEntry
+//objects, both directly and from its spill buffer.
+type droppingBackend struct {
+	captureBackend
+	dropped uint64
+	spilled uint64
+}
+
+func (b *droppingBackend) Dropped() uint64 {
+	return b.dropped
+}
+
+func (b *droppingBackend) SpillStats() SpillStats {
+	return SpillStats{Dropped: b.spilled}
+}
+
+func TestSummaryDropped(t *testing.T) {
+	logger := NewLogger()
+	logger.AddBackend("dropping", &droppingBackend{dropped: 3, spilled: 2})
+	logger.AddBackend("tcp", &TCPBackend{dropped: 4})
+
+	expect(t, logger.Summary().Dropped, uint64(9))
+}
@@ -0,0 +1,82 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package lumberjack
+
+import (
+	"fmt"
+	"log/syslog"
+	"sync"
+)
+
+//SyslogBackend implements a Backend that sends each LogEntry to a syslog
+//daemon as a formatted line, using the same formats as the PrintBackend based
+//on the Verbosity specified, with the syslog priority mapped from the LogLevel
+//of the LogEntry.
+type SyslogBackend struct {
+	Verbosity LogLevel
+
+	writer *syslog.Writer
+	sync.Mutex
+}
+
+//NewSyslogBackend connects to the syslog daemon at the specified address on
+//the specified network, such as "tcp" or "udp", and returns an instance of
+//SyslogBackend that sends each LogEntry to it tagged with the specified tag.
+//If network is empty, the local syslog daemon is used. An error is returned
+//if the connection can't be established.
+func NewSyslogBackend(network, addr, tag string) (*SyslogBackend, error) {
+	writer, err := syslog.Dial(network, addr, syslog.LOG_INFO|syslog.LOG_USER, tag)
+	if err != nil {
+		return nil, fmt.Errorf("Syslog Backend: unable to connect to syslog: %s", err)
+	}
+
+	return &SyslogBackend{Verbosity: ERROR, writer: writer}, nil
+}
+
+//Log satisfies the Backend interfaces requirements used for accepting
+//LogEntry objects to send to the syslog daemon.
+func (b *SyslogBackend) Log(entry *LogEntry) {
+	b.Lock()
+	defer b.Unlock()
+
+	line := formatEntry(b.Verbosity, entry.Level.String(), entry)
+
+	var err error
+	switch syslogPriority(entry.Level) {
+	case syslog.LOG_CRIT:
+		err = b.writer.Crit(line)
+	case syslog.LOG_ERR:
+		err = b.writer.Err(line)
+	case syslog.LOG_WARNING:
+		err = b.writer.Warning(line)
+	case syslog.LOG_DEBUG:
+		err = b.writer.Debug(line)
+	default:
+		err = b.writer.Info(line)
+	}
+
+	if err != nil {
+		logInternalf(ERROR, "Syslog Backend: unable to send LogEntry: %s", err)
+	}
+}
+
+//Close closes the connection to the syslog daemon.
+func (b *SyslogBackend) Close() error {
+	b.Lock()
+	defer b.Unlock()
+
+	if err := b.writer.Close(); err != nil {
+		return fmt.Errorf("Syslog Backend: unable to close connection: %s", err)
+	}
+	return nil
+}
+
+//syslogPriority returns the syslog severity that the specified LogLevel is
+//sent with, from syslogSeverities.
+func syslogPriority(level LogLevel) syslog.Priority {
+	if severity, exists := syslogSeverities[level]; exists {
+		return syslog.Priority(severity)
+	}
+	return syslog.LOG_INFO
+}
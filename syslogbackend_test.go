@@ -0,0 +1,57 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package lumberjack
+
+import (
+	"log/syslog"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSyslogPriority(t *testing.T) {
+	expect(t, syslogPriority(FATAL), syslog.LOG_CRIT)
+	expect(t, syslogPriority(CRITICAL), syslog.LOG_CRIT)
+	expect(t, syslogPriority(ERROR), syslog.LOG_ERR)
+	expect(t, syslogPriority(WARN), syslog.LOG_WARNING)
+	expect(t, syslogPriority(INFO), syslog.LOG_INFO)
+	expect(t, syslogPriority(DEBUG), syslog.LOG_DEBUG)
+}
+
+func TestSyslogPriorityMatchesFormatter(t *testing.T) {
+	for level, severity := range syslogSeverities {
+		expect(t, int(syslogPriority(level)), severity)
+	}
+}
+
+func TestSyslogBackendRemote(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	backend, err := NewSyslogBackend("udp", conn.LocalAddr().String(), "lumberjack")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer backend.Close()
+
+	entry := testobj.Entries[0]
+	backend.Log(&entry)
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 1024)
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// LOG_USER|LOG_ERR is priority 11.
+	message := string(buf[:n])
+	expect(t, strings.HasPrefix(message, "<11>"), true)
+	expect(t, strings.Contains(message, "lumberjack"), true)
+	expect(t, strings.HasSuffix(strings.TrimSpace(message), "(ERROR) @ main.main()() main.go:10: Test Error"), true)
+}
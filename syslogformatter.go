@@ -0,0 +1,157 @@
+package lumberjack
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//syslogSeverities maps each LogLevel to its RFC5424 severity, shared by the
+//Syslog5424Formatter and the SyslogBackend so that a LogEntry has the same
+//severity whichever way it reaches syslog. FATAL is Critical rather than
+//Emergency, as it ends the program rather than making the system unusable.
+var syslogSeverities = map[LogLevel]int{
+	FATAL:    2, //Critical
+	CRITICAL: 2, //Critical
+	ERROR:    3, //Error
+	WARN:     4, //Warning
+	INFO:     6, //Informational
+	DEBUG:    7, //Debug
+	TRACE:    7, //Debug
+}
+
+//Syslog5424Formatter implements a Formatter that renders each LogEntry as an
+//RFC5424 syslog line, for tools that expect syslog lines on a stream such as
+//os.Stdout or a file. The structured Fields are rendered as structured data
+//with the SD-ID specified by StructuredDataID, which defaults to
+//"fields@32473", and the component of the LogEntry is used as the MSGID.
+//
+//Facility defaults to 1 (user-level messages), Hostname to the name of the
+//host, AppName to the name of the running program, and ProcID to its process
+//ID. The timestamp holds the time of the LogEntry, or the time it was
+//formatted if the LogEntry has no time.
+type Syslog5424Formatter struct {
+	Facility         int
+	Hostname         string
+	AppName          string
+	ProcID           string
+	StructuredDataID string
+
+	clock Clock
+}
+
+//NewSyslog5424Formatter returns an instance of Syslog5424Formatter with the
+//defaults filled in from the running program.
+func NewSyslog5424Formatter() *Syslog5424Formatter {
+	hostname, _ := os.Hostname()
+	return &Syslog5424Formatter{
+		Facility:         1,
+		Hostname:         hostname,
+		AppName:          filepath.Base(os.Args[0]),
+		ProcID:           strconv.Itoa(os.Getpid()),
+		StructuredDataID: "fields@32473",
+	}
+}
+
+//Format satisfies the Formatter interface's requirements used for rendering
+//a LogEntry into an RFC5424 syslog line.
+func (f *Syslog5424Formatter) Format(entry *LogEntry) ([]byte, error) {
+	severity, exists := syslogSeverities[entry.Level]
+	if !exists {
+		return nil, fmt.Errorf("Invalid LogLevel: %d", entry.Level)
+	}
+
+	clock := f.clock
+	if clock == nil {
+		clock = systemClock{}
+	}
+
+	timestamp := entry.Time
+	if timestamp.IsZero() {
+		timestamp = clock.Now()
+	}
+
+	line := fmt.Sprintf("<%d>1 %s %s %s %s %s %s %s",
+		f.Facility*8+severity,
+		timestamp.UTC().Format("2006-01-02T15:04:05.000000Z07:00"),
+		syslogHeaderField(f.Hostname, 255),
+		syslogHeaderField(f.AppName, 48),
+		syslogHeaderField(f.ProcID, 128),
+		syslogHeaderField(entry.Component, 32),
+		f.structuredData(entry),
+		entry.Message,
+	)
+	return []byte(line), nil
+}
+
+//structuredData renders the structured Fields of the specified LogEntry as
+//an RFC5424 SD-ELEMENT, or the NILVALUE if it has none.
+func (f *Syslog5424Formatter) structuredData(entry *LogEntry) string {
+	if len(entry.Fields) == 0 {
+		return "-"
+	}
+
+	id := f.StructuredDataID
+	if id == "" {
+		id = "fields@32473"
+	}
+
+	keys := make([]string, 0, len(entry.Fields))
+	for key := range entry.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var sd strings.Builder
+	sd.WriteString("[" + syslogName(id))
+	for _, key := range keys {
+		value := fmt.Sprint(entry.Fields[key])
+		fmt.Fprintf(&sd, ` %s="%s"`, syslogName(key), syslogParamEscaper.Replace(value))
+	}
+	sd.WriteString("]")
+	return sd.String()
+}
+
+//syslogParamEscaper escapes the characters RFC5424 requires to be escaped
+//within a PARAM-VALUE.
+var syslogParamEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+
+//syslogHeaderField returns the specified value as an RFC5424 header field of
+//printable US-ASCII characters, truncated to the specified maximum length, or
+//the NILVALUE if it is empty.
+func syslogHeaderField(value string, max int) string {
+	value = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return '_'
+		}
+		return r
+	}, value)
+
+	if value == "" {
+		return "-"
+	}
+	if len(value) > max {
+		value = value[:max]
+	}
+	return value
+}
+
+//syslogName returns the specified value as an RFC5424 SD-NAME, replacing
+//any characters that aren't allowed and truncating it to 32 characters. The
+//"@" of an SD-ID is kept.
+func syslogName(value string) string {
+	value = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 || r == '=' || r == ']' || r == '"' {
+			return '_'
+		}
+		return r
+	}, value)
+
+	if len(value) > 32 {
+		value = value[:32]
+	}
+	return value
+}
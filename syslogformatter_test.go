@@ -0,0 +1,88 @@
+package lumberjack
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+//rfc5424 matches an RFC5424 syslog line, capturing the PRI, timestamp,
+//hostname, app name, procid, msgid, structured data, and message.
+var rfc5424 = regexp.MustCompile(`^<(\d{1,3})>1 (\S+) (\S{1,255}) (\S{1,48}) (\S{1,128}) (\S{1,32}) (-|(?:\[[^ =\]"]+(?: [^ =\]"]+="(?:[^"\\\]]|\\.)*")*\])+) (.*)$`)
+
+func TestSyslog5424Formatter(t *testing.T) {
+	formatter := &Syslog5424Formatter{
+		Facility: 1,
+		Hostname: "web-1",
+		AppName:  "myapp",
+		ProcID:   "1234",
+		clock:    newFakeClock(),
+	}
+
+	entry := testobj.Entries[0]
+	entry.Component = "db"
+	entry.SetField("user", `bob "the] builder`)
+	entry.SetField("attempt", 2)
+
+	data, err := formatter.Format(&entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	match := rfc5424.FindStringSubmatch(string(data))
+	if match == nil {
+		t.Fatalf("Expected RFC5424 line, got %q", data)
+	}
+
+	expect(t, match[1], strconv.Itoa(1*8+3))
+	expect(t, match[2], "2015-08-17T12:23:57.000000Z")
+	expect(t, match[3], "web-1")
+	expect(t, match[4], "myapp")
+	expect(t, match[5], "1234")
+	expect(t, match[6], "db")
+	expect(t, match[7], `[fields@32473 attempt="2" user="bob \"the\] builder"]`)
+	expect(t, match[8], "Test Error")
+}
+
+func TestSyslog5424FormatterPriority(t *testing.T) {
+	formatter := NewSyslog5424Formatter()
+	formatter.Facility = 16 //local0
+
+	cases := map[LogLevel]int{
+		FATAL:    130,
+		CRITICAL: 130,
+		ERROR:    131,
+		WARN:     132,
+		INFO:     134,
+		DEBUG:    135,
+	}
+
+	for level, priority := range cases {
+		entry := testobj.Entries[1]
+		entry.Level = level
+
+		data, err := formatter.Format(&entry)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		match := rfc5424.FindStringSubmatch(string(data))
+		if match == nil {
+			t.Fatalf("Expected RFC5424 line, got %q", data)
+		}
+		expect(t, match[1], strconv.Itoa(priority))
+		expect(t, match[6], "-")
+		expect(t, match[7], "-")
+	}
+}
+
+func TestWriterBackend(t *testing.T) {
+	var buf bytes.Buffer
+	backend := NewWriterBackend(&buf, &Syslog5424Formatter{Hostname: "web-1", clock: newFakeClock()})
+
+	entry := testobj.Entries[1]
+	backend.Log(&entry)
+
+	expect(t, buf.String(), "<6>1 2015-08-17T12:23:57.000000Z web-1 - - - - Test Info\n")
+}
@@ -0,0 +1,306 @@
+package lumberjack
+
+import (
+	"fmt"
+	"net"
+	"sync/atomic"
+	"time"
+)
+
+//TCPBackend implements a Backend that writes each LogEntry as a single JSON
+//object followed by a newline over a persistent TCP connection, such as to the
+//TCP input of Logstash or Fluentd.
+//
+//The connection is dialed lazily by an internal Goroutine, so that a slow or
+//unreachable address never blocks the application. If a write fails or
+//stalls, the connection is redialed and the line written again, backing off between
+//failed dials up to MaxTCPBackoff. While disconnected, up to TCPBufferSize
+//LogEntry objects wait to be written, and any more are dropped, as counted by
+//Dropped. A TCPBackend created with NewTCPBackendWithSpill instead moves them
+//into a spill buffer while disconnected, as with the SpillBuffer option of
+//the HttpClientBackend.
+//
+//Close should be called during cleanup code to write any waiting lines and
+//close down the internal Goroutine and connection.
+type TCPBackend struct {
+	dropped uint64 //First for 64-bit alignment of its atomic counter.
+	stopped int32
+	addr    string
+	entries chan LogEntry
+	spill   *spillBuffer
+	stop    chan struct{}
+	done    chan struct{}
+	conn    net.Conn
+	lost    uint64 //LogEntry objects not written while closing, set before done is closed.
+}
+
+//TCPBufferSize is the number of LogEntry objects a TCPBackend holds while
+//they wait to be written.
+const TCPBufferSize = 1000
+
+//MaxTCPBackoff is the longest a TCPBackend waits between failed dials.
+const MaxTCPBackoff = time.Second * 30
+
+//tcpDialTimeout is the longest a TCPBackend waits for a dial to complete.
+const tcpDialTimeout = time.Second * 5
+
+//tcpWriteTimeout is the longest a TCPBackend waits for a write to complete
+//before treating it as failed, so a peer that stops reading can't stall the
+//Goroutine forever. It is a variable so tests can shorten it.
+var tcpWriteTimeout = time.Second * 5
+
+//NewTCPBackend returns an instance of TCPBackend that writes each LogEntry to
+//the specified address, in the "host:port" form, and starts its Goroutine. The
+//address is not dialed until the first LogEntry is written.
+func NewTCPBackend(addr string) *TCPBackend {
+	return NewTCPBackendWithSpill(addr, 0)
+}
+
+//NewTCPBackendWithSpill returns an instance of TCPBackend like NewTCPBackend,
+//that holds the LogEntry objects that fail to be written in a bounded
+//in-memory spill buffer while disconnected, rather than leaving them to fill
+//the TCPBufferSize buffer and dropping newer ones. The spilled LogEntry
+//objects are replayed oldest-first ahead of newer ones once reconnected. Once
+//the spill buffer holds the specified capacity, the oldest LogEntry objects
+//are dropped to make room. A capacity of 0 disables the spill buffer.
+func NewTCPBackendWithSpill(addr string, capacity int) *TCPBackend {
+	b := &TCPBackend{
+		addr:    addr,
+		entries: make(chan LogEntry, TCPBufferSize),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	if capacity > 0 {
+		b.spill = newSpillBuffer(capacity)
+	}
+
+	go b.start()
+
+	return b
+}
+
+//Log satisfies the Backend interfaces requirements used for accepting
+//LogEntry objects to write out over TCP as JSON. The LogEntry is dropped if
+//the buffer of waiting LogEntry objects is full or the TCPBackend has been
+//closed.
+func (b *TCPBackend) Log(entry *LogEntry) {
+	if atomic.LoadInt32(&b.stopped) == 1 {
+		return
+	}
+
+	select {
+	case b.entries <- *entry:
+	default:
+		atomic.AddUint64(&b.dropped, 1)
+	}
+}
+
+//Dropped returns the number of LogEntry objects dropped by the current
+//TCPBackend because its buffer of waiting LogEntry objects was full, or
+//because they couldn't be written when it was closed. Those dropped from the
+//spill buffer are counted in its SpillStats instead.
+func (b *TCPBackend) Dropped() uint64 {
+	return atomic.LoadUint64(&b.dropped)
+}
+
+//SpillStats returns the statistics of the spill buffer of the current
+//TCPBackend. The zero value is returned if spilling is not enabled.
+func (b *TCPBackend) SpillStats() SpillStats {
+	if b.spill == nil {
+		return SpillStats{}
+	}
+	return b.spill.stats()
+}
+
+//Close writes any waiting lines, then stops the internal Goroutine of the
+//current TCPBackend and closes its connection, returning once it has exited.
+//Lines that can't be written without waiting to redial are dropped, and an
+//error reporting how many were lost is returned. Calling Close more than once
+//has no effect.
+func (b *TCPBackend) Close() error {
+	if !atomic.CompareAndSwapInt32(&b.stopped, 0, 1) {
+		return nil
+	}
+	close(b.stop)
+	<-b.done
+	if b.lost > 0 {
+		return fmt.Errorf("TCP Backend: %d lines to %s dropped while closing", b.lost, b.addr)
+	}
+	return nil
+}
+
+//start is an internal method used to start up the Goroutine that writes the
+//waiting LogEntry objects to the connection, redialing it as needed.
+func (b *TCPBackend) start() {
+	defer close(b.done)
+	defer b.disconnect()
+
+	backoff := time.Duration(0)
+
+	for {
+		if b.spill != nil && len(b.spill.entries()) > 0 {
+			if b.replaySpill() {
+				backoff = 0
+				continue
+			}
+			backoff = nextTCPBackoff(backoff)
+			if !b.wait(backoff) {
+				b.drain()
+				return
+			}
+			continue
+		}
+
+		select {
+		case entry := <-b.entries:
+			if b.write(&entry) {
+				backoff = 0
+				continue
+			}
+			if b.spill != nil {
+				b.spill.add([]LogEntry{entry})
+				continue
+			}
+			for !b.write(&entry) {
+				backoff = nextTCPBackoff(backoff)
+				if !b.wait(backoff) {
+					atomic.AddUint64(&b.dropped, 1)
+					b.lost++
+					b.drain()
+					return
+				}
+			}
+			backoff = 0
+
+		case <-b.stop:
+			b.drain()
+			return
+		}
+	}
+}
+
+//wait is an internal method used by the Goroutine to wait out the specified
+//backoff before redialing, moving any LogEntry objects that arrive meanwhile
+//into the spill buffer if it is enabled. It returns false if the TCPBackend
+//was stopped while waiting.
+func (b *TCPBackend) wait(backoff time.Duration) bool {
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+
+	var incoming chan LogEntry
+	if b.spill != nil {
+		incoming = b.entries
+	}
+
+	for {
+		select {
+		case <-timer.C:
+			return true
+		case entry := <-incoming:
+			b.spill.add([]LogEntry{entry})
+		case <-b.stop:
+			return false
+		}
+	}
+}
+
+//replaySpill is an internal method used by the Goroutine to write the
+//LogEntry objects in the spill buffer, oldest first. It returns true if they
+//were all written, otherwise those written are removed from the spill buffer
+//and the rest are left to be replayed once redialed.
+func (b *TCPBackend) replaySpill() bool {
+	for i, entry := range b.spill.entries() {
+		if !b.write(&entry) {
+			b.spill.replayN(i)
+			return false
+		}
+	}
+	b.spill.replay()
+	return true
+}
+
+//drain is an internal method used by the Goroutine to write any LogEntry
+//objects still spilled or waiting once stopped. After the first failed write,
+//the rest are dropped rather than waiting to redial, and counted as lost.
+func (b *TCPBackend) drain() {
+	failed := false
+	if b.spill != nil {
+		failed = !b.replaySpill()
+		if failed {
+			b.lost += uint64(len(b.spill.entries()))
+			b.spill.discard()
+		}
+	}
+
+	for {
+		select {
+		case entry := <-b.entries:
+			if failed || !b.write(&entry) {
+				failed = true
+				atomic.AddUint64(&b.dropped, 1)
+				b.lost++
+			}
+		default:
+			return
+		}
+	}
+}
+
+//write is an internal method used by the Goroutine to write the specified
+//LogEntry as a JSON line to the connection, dialing it if there is none, and
+//redialing it once if the write fails on a connection that may have gone
+//stale. A write that doesn't complete within tcpWriteTimeout is treated as
+//failed. It returns true if the line was written, or if the LogEntry can't be
+//Marshalled so there is nothing to retry.
+func (b *TCPBackend) write(entry *LogEntry) bool {
+	data, err := marshalEntryNamed(entry, false, nil)
+	if err != nil {
+		logInternalf(ERROR, "TCP Backend: unable to Marshal JSON from LogEntry: %s", err)
+		return true
+	}
+	line := append(data, '\n')
+
+	for attempt := 0; attempt < 2; attempt++ {
+		if b.conn == nil {
+			conn, err := net.DialTimeout("tcp", b.addr, tcpDialTimeout)
+			if err != nil {
+				logInternalf(ERROR, "TCP Backend: unable to connect to %s: %s", b.addr, err)
+				return false
+			}
+			b.conn = conn
+		}
+
+		err := b.conn.SetWriteDeadline(time.Now().Add(tcpWriteTimeout))
+		if err == nil {
+			_, err = b.conn.Write(line)
+		}
+		if err == nil {
+			return true
+		}
+		logInternalf(ERROR, "TCP Backend: unable to write to %s: %s", b.addr, err)
+		b.disconnect()
+	}
+	return false
+}
+
+//disconnect is an internal method used by the Goroutine to close the current
+//connection, if any.
+func (b *TCPBackend) disconnect() {
+	if b.conn != nil {
+		b.conn.Close()
+		b.conn = nil
+	}
+}
+
+//nextTCPBackoff is an internal function that returns the time.Duration to wait
+//before the next dial, doubling the specified previous backoff up to
+//MaxTCPBackoff, starting from 100 milliseconds.
+func nextTCPBackoff(previous time.Duration) time.Duration {
+	if previous <= 0 {
+		return time.Millisecond * 100
+	}
+	if previous*2 > MaxTCPBackoff {
+		return MaxTCPBackoff
+	}
+	return previous * 2
+}
@@ -0,0 +1,254 @@
+package lumberjack
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+//acceptLines accepts connections on the specified net.Listener until it is
+//closed, sending each line read from them on the returned channel.
+func acceptLines(t *testing.T, ln net.Listener) (<-chan map[string]interface{}, <-chan net.Conn) {
+	lines := make(chan map[string]interface{}, 100)
+	conns := make(chan net.Conn, 10)
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conns <- conn
+
+			go func() {
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					out := map[string]interface{}{}
+					if err := json.Unmarshal(scanner.Bytes(), &out); err != nil {
+						t.Errorf("Unable to Unmarshal line %q: %s", scanner.Text(), err)
+						continue
+					}
+					lines <- out
+				}
+			}()
+		}
+	}()
+
+	return lines, conns
+}
+
+func TestTCPBackend(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	lines, _ := acceptLines(t, ln)
+
+	backend := NewTCPBackend(ln.Addr().String())
+	defer backend.Close()
+
+	for i := 0; i < 2; i++ {
+		entry := testobj.Entries[i]
+		backend.Log(&entry)
+	}
+
+	for i := 0; i < 2; i++ {
+		select {
+		case out := <-lines:
+			expect(t, out["message"], testobj.Entries[i].Message)
+			expect(t, out["level"], testobj.Entries[i].Level.String())
+		case <-time.After(time.Second * 5):
+			t.Fatalf("Timed out waiting for line %d", i)
+		}
+	}
+
+	expect(t, backend.Dropped(), uint64(0))
+}
+
+func TestTCPBackendReconnect(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+
+	lines, conns := acceptLines(t, ln)
+
+	backend := NewTCPBackend(addr)
+	defer backend.Close()
+
+	entry := testobj.Entries[0]
+	backend.Log(&entry)
+
+	select {
+	case <-lines:
+	case <-time.After(time.Second * 5):
+		t.Fatal("Timed out waiting for the first line")
+	}
+
+	//Bounce the listener, dropping the established connection.
+	ln.Close()
+	(<-conns).Close()
+
+	ln, err = net.Listen("tcp", addr)
+	if err != nil {
+		t.Skipf("Unable to listen on %s again: %s", addr, err)
+	}
+	defer ln.Close()
+
+	lines, _ = acceptLines(t, ln)
+
+	//The first write after the bounce may succeed on the stale connection and
+	//be lost, so keep logging until a line arrives on the new one.
+	deadline := time.After(time.Second * 10)
+	for {
+		entry := testobj.Entries[1]
+		backend.Log(&entry)
+
+		select {
+		case out := <-lines:
+			expect(t, out["message"], testobj.Entries[1].Message)
+			return
+		case <-time.After(time.Millisecond * 50):
+		case <-deadline:
+			t.Fatal("Timed out waiting for a line after reconnecting")
+		}
+	}
+}
+
+func TestTCPBackendDropsWhenFull(t *testing.T) {
+	//Nothing listens on this address, so lines wait in the buffer.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	backend := NewTCPBackend(addr)
+
+	entry := testobj.Entries[0]
+	for i := 0; i < TCPBufferSize+10; i++ {
+		backend.Log(&entry)
+	}
+
+	if backend.Dropped() == 0 {
+		t.Error("Expected lines to be dropped while disconnected with a full buffer")
+	}
+
+	//The lines still waiting can't be written, so Close reports them.
+	if err := backend.Close(); err == nil {
+		t.Error("Expected Close to report the lines dropped while closing")
+	}
+	expect(t, backend.Close(), nil)
+
+	dropped := backend.Dropped()
+	backend.Log(&entry)
+	expect(t, backend.Dropped(), dropped)
+}
+
+func TestTCPBackendSpill(t *testing.T) {
+	//Reserve an address, then close it so nothing is listening yet.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	backend := NewTCPBackendWithSpill(addr, 3)
+	defer backend.Close()
+
+	for i := 0; i < 5; i++ {
+		entry := testobj.Entries[0]
+		entry.Message = fmt.Sprintf("spilled %d", i)
+		backend.Log(&entry)
+	}
+
+	//The oldest are dropped once the spill buffer is full.
+	deadline := time.Now().Add(time.Second * 5)
+	for backend.SpillStats().Dropped < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond * 10)
+	}
+	stats := backend.SpillStats()
+	expect(t, stats.Spilled, uint64(5))
+	expect(t, stats.Dropped, uint64(2))
+	expect(t, stats.Pending, uint64(3))
+
+	ln, err = net.Listen("tcp", addr)
+	if err != nil {
+		t.Skipf("Unable to listen on %s again: %s", addr, err)
+	}
+	defer ln.Close()
+
+	lines, _ := acceptLines(t, ln)
+
+	//Once reconnected, the spilled entries are replayed oldest first.
+	for i := 2; i < 5; i++ {
+		select {
+		case out := <-lines:
+			expect(t, out["message"], fmt.Sprintf("spilled %d", i))
+		case <-time.After(time.Second * 10):
+			t.Fatalf("Timed out waiting for spilled line %d", i)
+		}
+	}
+
+	//The spill buffer is emptied once the last of them is written.
+	deadline = time.Now().Add(time.Second * 5)
+	for backend.SpillStats().Pending > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond * 10)
+	}
+	stats = backend.SpillStats()
+	expect(t, stats.Replayed, uint64(3))
+	expect(t, stats.Pending, uint64(0))
+	expect(t, backend.Dropped(), uint64(0))
+}
+
+func TestTCPBackendWriteTimeout(t *testing.T) {
+	defer func(timeout time.Duration) { tcpWriteTimeout = timeout }(tcpWriteTimeout)
+	tcpWriteTimeout = time.Millisecond * 50
+
+	//Accept a single connection but never read from it, so writes eventually
+	//block, and refuse any redials.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	release := make(chan struct{})
+	defer close(release)
+	go func() {
+		conn, err := ln.Accept()
+		ln.Close()
+		if err == nil {
+			<-release
+			conn.Close()
+		}
+	}()
+
+	backend := NewTCPBackend(ln.Addr().String())
+
+	entry := testobj.Entries[0]
+	entry.Message = strings.Repeat("x", 64*1024)
+	for i := 0; i < TCPBufferSize; i++ {
+		backend.Log(&entry)
+	}
+
+	closed := make(chan error, 1)
+	go func() { closed <- backend.Close() }()
+
+	select {
+	case err := <-closed:
+		if err == nil {
+			t.Error("Expected Close to report the lines dropped while closing")
+		}
+	case <-time.After(time.Second * 10):
+		t.Fatal("Timed out waiting for Close with a peer that never reads")
+	}
+}
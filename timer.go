@@ -0,0 +1,63 @@
+package lumberjack
+
+import (
+	"fmt"
+	"time"
+)
+
+//Timer starts timing the operation with the specified name and returns a
+//function that, when called, logs a LogEntry with the operation name in the
+//"operation" field and the time elapsed since Timer was called in the
+//"duration_ms" field, in milliseconds. The LogEntry is logged at INFO unless
+//another LogLevel is set with SetTimerLevel.
+//
+//Additional fields can be specified as alternating key and value arguments
+//to the returned function.
+func (l *Logger) Timer(name string) func(keysAndValues ...interface{}) {
+	start := l.now()
+
+	return func(keysAndValues ...interface{}) {
+		elapsed := l.now().Sub(start)
+
+		l.Lock()
+		level := l.timerLevel
+		l.Unlock()
+
+		skip, ok := l.begin(level)
+		if !ok {
+			return
+		}
+
+		l.logWith(level, skip, fmt.Sprintf("%s took %s", name, elapsed), func(entry *LogEntry) {
+			for key, value := range kvFields(keysAndValues) {
+				entry.SetField(key, value)
+			}
+			entry.SetField("operation", name)
+			entry.SetField("duration_ms", float64(elapsed)/float64(time.Millisecond))
+		})
+	}
+}
+
+//SetTimerLevel sets the LogLevel the LogEntry objects of the functions
+//returned by Timer are logged at.
+func (l *Logger) SetTimerLevel(level LogLevel) error {
+	if !validLevel(level) {
+		return fmt.Errorf("Invalid LogLevel: %d", level)
+	}
+	l.Lock()
+	defer l.Unlock()
+	l.timerLevel = level
+	return nil
+}
+
+//now returns the current time from the Clock of the current Logger.
+func (l *Logger) now() time.Time {
+	l.Lock()
+	clock := l.clock
+	l.Unlock()
+
+	if clock == nil {
+		return time.Now()
+	}
+	return clock.Now()
+}
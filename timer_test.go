@@ -0,0 +1,48 @@
+package lumberjack
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimer(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+	logger.AddLevel(DEBUG)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	clock := newFakeClock()
+	logger.clock = clock
+
+	done := logger.Timer("load users")
+	clock.Advance(1500 * time.Millisecond)
+	done("count", 3)
+
+	if err := logger.SetTimerLevel(DEBUG); err != nil {
+		t.Fatal(err)
+	}
+
+	done = logger.Timer("save users")
+	clock.Advance(250 * time.Microsecond)
+	done()
+
+	entries := capture.Entries()
+	expect(t, len(entries), 2)
+
+	expect(t, entries[0].Level, INFO)
+	expect(t, entries[0].Fields["operation"], "load users")
+	expect(t, entries[0].Fields["duration_ms"], float64(1500))
+	expect(t, entries[0].Fields["count"], 3)
+	expect(t, entries[0].File, "timer_test.go")
+	expect(t, strings.HasPrefix(entries[0].Message, "load users took"), true)
+
+	expect(t, entries[1].Level, DEBUG)
+	expect(t, entries[1].Fields["duration_ms"], 0.25)
+
+	if err := logger.SetTimerLevel(LogLevel(42)); err == nil {
+		t.Error("Expected error for invalid LogLevel")
+	}
+}
@@ -0,0 +1,101 @@
+package lumberjack
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+//TimingRecorderBackend implements a Backend that records the time of each
+//call to Log, along with the latency between the LogEntry being built and it
+//reaching the Backend. It is intended for performance tests of code that logs
+//heavily, so that assertions can be made that logging stayed within a latency
+//budget, or that entries were delayed by contention.
+type TimingRecorderBackend struct {
+	clock     Clock
+	times     []time.Time
+	latencies []time.Duration
+	sync.Mutex
+}
+
+//NewTimingRecorderBackend returns an empty instance of TimingRecorderBackend.
+func NewTimingRecorderBackend() *TimingRecorderBackend {
+	return &TimingRecorderBackend{clock: systemClock{}}
+}
+
+//Log satisfies the Backend interfaces requirements used for accepting
+//LogEntry objects to record the timing of.
+func (b *TimingRecorderBackend) Log(entry *LogEntry) {
+	b.Lock()
+	defer b.Unlock()
+
+	now := b.clock.Now()
+	b.times = append(b.times, now)
+	if !entry.Time.IsZero() {
+		b.latencies = append(b.latencies, now.Sub(entry.Time))
+	}
+}
+
+//Times returns a copy of the time of each call to Log recorded so far, in the
+//order they were made.
+func (b *TimingRecorderBackend) Times() []time.Time {
+	b.Lock()
+	defer b.Unlock()
+	return append([]time.Time(nil), b.times...)
+}
+
+//Intervals returns the time elapsed between each call to Log recorded so far
+//and the call before it.
+func (b *TimingRecorderBackend) Intervals() []time.Duration {
+	b.Lock()
+	defer b.Unlock()
+
+	var intervals []time.Duration
+	for i := 1; i < len(b.times); i++ {
+		intervals = append(intervals, b.times[i].Sub(b.times[i-1]))
+	}
+	return intervals
+}
+
+//Latencies returns a copy of the latency of each LogEntry recorded so far,
+//measured from the time of the LogEntry to the call to Log.
+func (b *TimingRecorderBackend) Latencies() []time.Duration {
+	b.Lock()
+	defer b.Unlock()
+	return append([]time.Duration(nil), b.latencies...)
+}
+
+//Percentile returns the specified percentile, from 0 to 100, of the latencies
+//recorded so far, using the nearest rank method. If nothing has been recorded,
+//0 is returned.
+func (b *TimingRecorderBackend) Percentile(p float64) time.Duration {
+	return percentile(b.Latencies(), p)
+}
+
+//Reset discards every timing recorded so far.
+func (b *TimingRecorderBackend) Reset() {
+	b.Lock()
+	defer b.Unlock()
+	b.times = nil
+	b.latencies = nil
+}
+
+//percentile returns the specified percentile, from 0 to 100, of the specified
+//durations using the nearest rank method.
+func percentile(durations []time.Duration, p float64) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	rank := int(math.Ceil(p / 100 * float64(len(durations))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(durations) {
+		rank = len(durations)
+	}
+	return durations[rank-1]
+}
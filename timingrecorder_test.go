@@ -0,0 +1,58 @@
+package lumberjack
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimingRecorderBackend(t *testing.T) {
+	backend := NewTimingRecorderBackend()
+	clock := newFakeClock()
+	backend.clock = clock
+
+	// Entries take 1ms, 2ms, ... 10ms to reach the backend, 5ms apart.
+	for i := 1; i <= 10; i++ {
+		entry := testobj.Entries[1]
+		entry.Time = clock.Now().Add(-time.Duration(i) * time.Millisecond)
+		backend.Log(&entry)
+		clock.Advance(5 * time.Millisecond)
+	}
+
+	expect(t, len(backend.Times()), 10)
+	expect(t, len(backend.Latencies()), 10)
+
+	intervals := backend.Intervals()
+	expect(t, len(intervals), 9)
+	for _, interval := range intervals {
+		expect(t, interval, 5*time.Millisecond)
+	}
+
+	expect(t, backend.Percentile(50), 5*time.Millisecond)
+	expect(t, backend.Percentile(90), 9*time.Millisecond)
+	expect(t, backend.Percentile(100), 10*time.Millisecond)
+	expect(t, backend.Percentile(0), time.Millisecond)
+
+	backend.Reset()
+	expect(t, len(backend.Times()), 0)
+	expect(t, backend.Percentile(50), time.Duration(0))
+}
+
+func TestTimingRecorderBackendBurst(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+
+	backend := NewTimingRecorderBackend()
+	logger.AddBackend("timing", backend)
+
+	for i := 0; i < 100; i++ {
+		logger.Info("burst")
+	}
+
+	expect(t, len(backend.Times()), 100)
+	expect(t, len(backend.Latencies()), 100)
+
+	p50, p99 := backend.Percentile(50), backend.Percentile(99)
+	if p50 < 0 || p99 < p50 || p99 > time.Second {
+		t.Errorf("Implausible percentiles: p50 %s, p99 %s", p50, p99)
+	}
+}
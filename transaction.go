@@ -0,0 +1,67 @@
+package lumberjack
+
+import "context"
+
+//transactionKey is the context key used to hold the transaction name.
+type transactionKey struct{}
+
+//traceIDKey is the context key used to hold the trace ID.
+type traceIDKey struct{}
+
+//TraceIDField is the name of the structured field the trace ID held by the
+//context.Context of a Logger derived with WithContext is set in.
+const TraceIDField = "trace_id"
+
+//WithTransaction returns a child Logger that shares the configuration of the
+//current Logger and tags every LogEntry it sends with the specified
+//human-readable transaction name, such as the route template "GET /users/:id",
+//for correlating the logs of a single operation.
+func (l *Logger) WithTransaction(name string) *Logger {
+	child := *l
+	child.transaction = name
+	return &child
+}
+
+//WithContext returns a child Logger that attaches the specified
+//context.Context to every LogEntry it sends, and tags them with the
+//transaction name and trace ID held by the context.Context, if any, with the
+//trace ID set in the TraceIDField structured field. This allows for a request
+//scoped Logger to be derived once per request, such as in an http.Handler,
+//and every LogEntry it sends to be correlated.
+func (l *Logger) WithContext(ctx context.Context) *Logger {
+	child := *l
+	child.ctx = ctx
+	if name := TransactionFromContext(ctx); name != "" {
+		child.transaction = name
+	}
+	if id := TraceIDFromContext(ctx); id != "" {
+		return child.WithField(TraceIDField, id)
+	}
+	return &child
+}
+
+//ContextWithTransaction returns a copy of the specified context.Context
+//holding the specified transaction name.
+func ContextWithTransaction(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, transactionKey{}, name)
+}
+
+//TransactionFromContext returns the transaction name held by the specified
+//context.Context, or an empty string if it holds none.
+func TransactionFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(transactionKey{}).(string)
+	return name
+}
+
+//ContextWithTraceID returns a copy of the specified context.Context holding
+//the specified trace ID, such as one received in a request header.
+func ContextWithTraceID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, id)
+}
+
+//TraceIDFromContext returns the trace ID held by the specified
+//context.Context, or an empty string if it holds none.
+func TraceIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(traceIDKey{}).(string)
+	return id
+}
@@ -0,0 +1,76 @@
+package lumberjack
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithContextTransaction(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	ctx := ContextWithTransaction(context.Background(), "GET /users/:id")
+	logger.WithContext(ctx).Info("inside")
+	logger.WithContext(context.Background()).Info("outside")
+	logger.WithContext(ctx).WithComponent("db").Info("component")
+
+	entries := capture.Entries()
+	expect(t, len(entries), 3)
+	expect(t, entries[0].Transaction, "GET /users/:id")
+	expect(t, entries[1].Transaction, "")
+	expect(t, entries[2].Transaction, "GET /users/:id")
+	expect(t, entries[2].Component, "db")
+}
+
+func TestWithContextTraceID(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+	logger.AddLevel(ERROR)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	ctx := ContextWithTraceID(context.Background(), "4bf92f3577b34da6")
+	requestLogger := logger.WithContext(ctx).WithField("user", 42)
+	requestLogger.Info("first")
+	requestLogger.ErrorErr(nil, "second")
+	logger.Info("untraced")
+
+	entries := capture.Entries()
+	expect(t, len(entries), 3)
+	expect(t, entries[0].Fields[TraceIDField], "4bf92f3577b34da6")
+	expect(t, entries[0].Fields["user"], 42)
+	expect(t, entries[1].Fields[TraceIDField], "4bf92f3577b34da6")
+	expect(t, entries[1].Context, ctx)
+	_, traced := entries[2].Fields[TraceIDField]
+	expect(t, traced, false)
+}
+
+func TestHTTPAccessMiddlewareTransaction(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	config := DefaultAccessLogConfig
+	config.Transaction = func(r *http.Request) string { return r.Method + " /users/:id" }
+
+	handler := logger.HTTPAccessMiddlewareWithConfig(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger.WithContext(r.Context()).Info("loading user")
+	}), config)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/42", nil))
+
+	entries := capture.Entries()
+	expect(t, len(entries), 2)
+	expect(t, entries[0].Message, "loading user")
+	expect(t, entries[0].Transaction, "GET /users/:id")
+	expect(t, entries[1].Transaction, "GET /users/:id")
+	expect(t, entries[1].Fields["path"], "/users/42")
+}
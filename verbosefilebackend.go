@@ -0,0 +1,86 @@
+package lumberjack
+
+import "sync"
+
+//VerboseFileBackend implements a Backend that writes INFO and more severe
+//LogEntry objects to a main log file, and can additionally write every
+//LogEntry, including DEBUG, to a separate verbose log file that is toggled
+//on and off at runtime, such as while investigating an incident.
+//
+//The Logger it is added to must have the verbose LogLevels added for them
+//to reach the verbose log file.
+type VerboseFileBackend struct {
+	main        *FileBackend
+	verbose     *FileBackend
+	verbosePath string
+	sync.Mutex
+}
+
+//NewVerboseFileBackend opens the main log file at the specified path and
+//returns an instance of VerboseFileBackend with the verbose log file at the
+//specified verbosePath turned off. An error is returned if the main log file
+//can't be opened.
+func NewVerboseFileBackend(path string, verbosePath string) (*VerboseFileBackend, error) {
+	main, err := NewFileBackend(path)
+	if err != nil {
+		return nil, err
+	}
+	return &VerboseFileBackend{main: main, verbosePath: verbosePath}, nil
+}
+
+//Log satisfies the Backend interfaces requirements used for accepting
+//LogEntry objects to write out to the main and verbose log files.
+func (b *VerboseFileBackend) Log(entry *LogEntry) {
+	if entry.Level >= INFO {
+		b.main.Log(entry)
+	}
+
+	b.Lock()
+	verbose := b.verbose
+	b.Unlock()
+
+	if verbose != nil {
+		verbose.Log(entry)
+	}
+}
+
+//EnableVerbose opens the verbose log file, creating it if it does not exist,
+//and starts writing every LogEntry to it. An error is returned if the verbose
+//log file can't be opened.
+func (b *VerboseFileBackend) EnableVerbose() error {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.verbose != nil {
+		return nil
+	}
+
+	verbose, err := NewFileBackend(b.verbosePath)
+	if err != nil {
+		return err
+	}
+	b.verbose = verbose
+	return nil
+}
+
+//DisableVerbose stops writing to the verbose log file and closes it.
+func (b *VerboseFileBackend) DisableVerbose() error {
+	b.Lock()
+	verbose := b.verbose
+	b.verbose = nil
+	b.Unlock()
+
+	if verbose == nil {
+		return nil
+	}
+	return verbose.Close()
+}
+
+//Close closes the main log file, and the verbose log file if it is enabled.
+func (b *VerboseFileBackend) Close() error {
+	verboseErr := b.DisableVerbose()
+	if err := b.main.Close(); err != nil {
+		return err
+	}
+	return verboseErr
+}
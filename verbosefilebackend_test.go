@@ -0,0 +1,58 @@
+package lumberjack
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVerboseFileBackend(t *testing.T) {
+	path, cleanup := tempLogPath(t)
+	defer cleanup()
+	verbosePath := filepath.Join(filepath.Dir(path), "verbose.log")
+
+	backend, err := NewVerboseFileBackend(path, verbosePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer backend.Close()
+
+	logger := NewLogger()
+	logger.AddLevel(DEBUG)
+	logger.AddLevel(INFO)
+	logger.AddBackend("file", backend)
+
+	logger.Debug("debug before")
+	logger.Info("info before")
+
+	// DEBUG doesn't go anywhere while verbose is off.
+	expect(t, fileExists(verbosePath), false)
+	lines := readLines(t, path)
+	expect(t, len(lines), 1)
+	expect(t, strings.Contains(lines[0], "info before"), true)
+
+	if err := backend.EnableVerbose(); err != nil {
+		t.Fatal(err)
+	}
+
+	logger.Debug("debug during")
+	logger.Info("info during")
+
+	// DEBUG only goes to the verbose file, INFO goes to both.
+	lines = readLines(t, path)
+	expect(t, len(lines), 2)
+	expect(t, strings.Contains(lines[1], "info during"), true)
+
+	verbose := readLines(t, verbosePath)
+	expect(t, len(verbose), 2)
+	expect(t, strings.Contains(verbose[0], "debug during"), true)
+	expect(t, strings.Contains(verbose[1], "info during"), true)
+
+	if err := backend.DisableVerbose(); err != nil {
+		t.Fatal(err)
+	}
+
+	logger.Debug("debug after")
+	expect(t, len(readLines(t, verbosePath)), 2)
+	expect(t, len(readLines(t, path)), 2)
+}
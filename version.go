@@ -0,0 +1,58 @@
+package lumberjack
+
+import (
+	"runtime/debug"
+	"sync"
+)
+
+//buildVersion holds the version of the main module read from the build
+//info of the running binary, which is only read once.
+var (
+	buildVersion     string
+	buildVersionOnce sync.Once
+)
+
+//EnableVersion turns on tagging every LogEntry sent by the current Logger
+//with the version of the main module of the running binary, which allows
+//for correlating logs with deployed versions. The version is read from the
+//build info of the binary, preferring the module version and falling back
+//to the VCS revision when built with Go 1.18 or later. If no build info is
+//available, such as when using go run, the Version field is left empty.
+func (l *Logger) EnableVersion() {
+	version := moduleVersion()
+	l.Lock()
+	defer l.Unlock()
+	l.version = version
+}
+
+//DisableVersion turns off tagging every LogEntry sent by the current Logger
+//with the version of the main module.
+func (l *Logger) DisableVersion() {
+	l.Lock()
+	defer l.Unlock()
+	l.version = ""
+}
+
+//moduleVersion returns the version of the main module of the running binary,
+//reading it from the build info the first time it is called.
+func moduleVersion() string {
+	buildVersionOnce.Do(func() {
+		buildVersion = versionFromBuildInfo(debug.ReadBuildInfo())
+	})
+	return buildVersion
+}
+
+//versionFromBuildInfo returns the version of the main module from the
+//specified build info, falling back to the VCS revision if the module
+//version is not known. An empty string is returned if neither is available.
+func versionFromBuildInfo(info *debug.BuildInfo, ok bool) string {
+	if !ok || info == nil {
+		return ""
+	}
+
+	if v := info.Main.Version; v != "" && v != "(devel)" {
+		return v
+	}
+
+	return vcsRevision(info)
+}
@@ -0,0 +1,17 @@
+//go:build go1.18
+// +build go1.18
+
+package lumberjack
+
+import "runtime/debug"
+
+//vcsRevision returns the VCS revision recorded in the settings of the
+//specified build info, or an empty string if none is recorded.
+func vcsRevision(info *debug.BuildInfo) string {
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" {
+			return setting.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,22 @@
+//go:build go1.18
+// +build go1.18
+
+package lumberjack
+
+import (
+	"runtime/debug"
+	"testing"
+)
+
+func TestVersionFromBuildInfoVCSRevision(t *testing.T) {
+	// Module version is preferred.
+	info := &debug.BuildInfo{
+		Main:     debug.Module{Path: "example.com/app", Version: "v1.2.3"},
+		Settings: []debug.BuildSetting{{Key: "vcs.revision", Value: "abc123"}},
+	}
+	expect(t, versionFromBuildInfo(info, true), "v1.2.3")
+
+	// Development builds fall back to the VCS revision.
+	info.Main.Version = "(devel)"
+	expect(t, versionFromBuildInfo(info, true), "abc123")
+}
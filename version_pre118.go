@@ -0,0 +1,12 @@
+//go:build !go1.18
+// +build !go1.18
+
+package lumberjack
+
+import "runtime/debug"
+
+//vcsRevision returns an empty string, as the build info only records the VCS
+//revision from Go 1.18, leaving only the module version to go by.
+func vcsRevision(info *debug.BuildInfo) string {
+	return ""
+}
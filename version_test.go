@@ -0,0 +1,46 @@
+package lumberjack
+
+import (
+	"runtime/debug"
+	"testing"
+)
+
+func TestVersionFromBuildInfo(t *testing.T) {
+	// Module version is preferred.
+	info := &debug.BuildInfo{
+		Main: debug.Module{Path: "example.com/app", Version: "v1.2.3"},
+	}
+	expect(t, versionFromBuildInfo(info, true), "v1.2.3")
+
+	// Nothing is known about the version.
+	info.Main.Version = "(devel)"
+	expect(t, versionFromBuildInfo(info, true), "")
+
+	// No build info is available.
+	expect(t, versionFromBuildInfo(nil, false), "")
+}
+
+func TestEnableVersion(t *testing.T) {
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+
+	capture := &captureBackend{}
+	logger.AddBackend("capture", capture)
+
+	logger.Info("untagged")
+
+	logger.EnableVersion()
+	logger.Lock()
+	logger.version = "v1.2.3" // Tests have no main module version to read.
+	logger.Unlock()
+	logger.Info("tagged")
+
+	logger.DisableVersion()
+	logger.Info("untagged")
+
+	entries := capture.Entries()
+	expect(t, len(entries), 3)
+	expect(t, entries[0].Version, "")
+	expect(t, entries[1].Version, "v1.2.3")
+	expect(t, entries[2].Version, "")
+}
@@ -0,0 +1,232 @@
+package lumberjack
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+//maxWALRecordBytes is the largest record ReplayWAL accepts, so that a corrupt
+//length prefix doesn't cause a huge allocation.
+const maxWALRecordBytes = 64 << 20
+
+//WALBackend implements a Backend that appends each LogEntry to a write-ahead
+//log file, so that the LogEntry objects of an application that crashes can be
+//recovered with ReplayWAL on restart, such as to forward them to a network
+//Backend. Each record is the LogEntry as JSON, preceded by its length as a
+//4 byte big-endian integer, written with a single write call.
+//
+//Records are handed to the operating system as they are logged, so they
+//survive the application crashing. To also survive the machine crashing, the
+//file is synced to disk either after every record, or periodically.
+type WALBackend struct {
+	file         *os.File
+	syncInterval time.Duration
+	dirty        bool
+	stop         chan struct{}
+	closed       bool
+	sync.Mutex
+}
+
+//NewWALBackend opens the write-ahead log file at the specified path for
+//appending, creating it if it does not exist, and returns an instance of
+//WALBackend that writes each LogEntry to it. The file is synced to disk at the
+//specified interval, or after every record if no interval is specified. An
+//error is returned if the file can't be opened.
+func NewWALBackend(path string, syncInterval time.Duration) (*WALBackend, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("WAL Backend: unable to open log file: %s", err)
+	}
+
+	b := &WALBackend{
+		file:         file,
+		syncInterval: syncInterval,
+		stop:         make(chan struct{}),
+	}
+
+	if syncInterval > 0 {
+		go b.startSyncer()
+	}
+
+	return b, nil
+}
+
+//Log satisfies the Backend interfaces requirements used for accepting
+//LogEntry objects to append to the write-ahead log.
+func (b *WALBackend) Log(entry *LogEntry) {
+	data, err := marshalEntry(entry, false)
+	if err != nil {
+		logInternalf(ERROR, "WAL Backend: unable to Marshal JSON from LogEntry: %s", err)
+		return
+	}
+
+	record := make([]byte, 4+len(data))
+	binary.BigEndian.PutUint32(record, uint32(len(data)))
+	copy(record[4:], data)
+
+	b.Lock()
+	defer b.Unlock()
+
+	if b.closed {
+		return
+	}
+
+	if _, err := b.file.Write(record); err != nil {
+		logInternalf(ERROR, "WAL Backend: unable to write LogEntry: %s", err)
+		return
+	}
+	b.dirty = true
+
+	if b.syncInterval <= 0 {
+		if err := b.sync(); err != nil {
+			logInternal(ERROR, err)
+		}
+	}
+}
+
+//Sync syncs any records written since the last sync to disk.
+func (b *WALBackend) Sync() error {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.closed {
+		return nil
+	}
+	return b.sync()
+}
+
+//Close syncs any records written since the last sync to disk and closes the
+//write-ahead log file. Calling Close more than once has no effect.
+func (b *WALBackend) Close() error {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.closed {
+		return nil
+	}
+	b.closed = true
+	close(b.stop)
+
+	syncErr := b.sync()
+	if err := b.file.Close(); err != nil {
+		return fmt.Errorf("WAL Backend: unable to close log file: %s", err)
+	}
+	return syncErr
+}
+
+//sync is an internal method that syncs the write-ahead log file to disk if
+//any records were written since the last sync. The caller must hold the lock.
+func (b *WALBackend) sync() error {
+	if !b.dirty {
+		return nil
+	}
+	if err := b.file.Sync(); err != nil {
+		return fmt.Errorf("WAL Backend: unable to sync log file: %s", err)
+	}
+	b.dirty = false
+	return nil
+}
+
+//startSyncer is an internal method used to start up the Goroutine that
+//periodically syncs the write-ahead log file to disk.
+func (b *WALBackend) startSyncer() {
+	ticker := time.NewTicker(b.syncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := b.Sync(); err != nil {
+				logInternal(ERROR, err)
+			}
+		case <-b.stop:
+			return
+		}
+	}
+}
+
+//ReplayWAL reads the write-ahead log file written by a WALBackend at the
+//specified path and sends every LogEntry in it to the specified backends, in
+//the order they were logged, returning the number of LogEntry objects
+//replayed. A record cut short by a crash at the end of the file is ignored. An
+//error is returned if the file can't be read or holds a corrupt record.
+//
+//The file is left as it is, so replaying it again sends the same LogEntry
+//objects again. Use ReplayAndTruncateWAL to empty it once replayed.
+func ReplayWAL(path string, backends ...Backend) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("WAL Backend: unable to open log file: %s", err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	header := make([]byte, 4)
+	replayed := 0
+
+	for {
+		if _, err := io.ReadFull(reader, header); err == io.EOF || err == io.ErrUnexpectedEOF {
+			return replayed, nil
+		} else if err != nil {
+			return replayed, fmt.Errorf("WAL Backend: unable to read log file: %s", err)
+		}
+
+		size := binary.BigEndian.Uint32(header)
+		if size > maxWALRecordBytes {
+			return replayed, fmt.Errorf("WAL Backend: corrupt record %d, length of %d bytes", replayed+1, size)
+		}
+
+		data := make([]byte, size)
+		if _, err := io.ReadFull(reader, data); err == io.EOF || err == io.ErrUnexpectedEOF {
+			return replayed, nil //The last record was only partially written.
+		} else if err != nil {
+			return replayed, fmt.Errorf("WAL Backend: unable to read log file: %s", err)
+		}
+
+		var entry LogEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return replayed, fmt.Errorf("WAL Backend: corrupt record %d: %s", replayed+1, err)
+		}
+
+		for _, backend := range backends {
+			backend.Log(&entry)
+		}
+		replayed++
+	}
+}
+
+//ReplayAndTruncateWAL replays the write-ahead log file at the specified path
+//to the specified backends like ReplayWAL, then flushes the backends that
+//implement the Flusher interface and truncates the file, so that the same
+//LogEntry objects aren't replayed again on the next restart. The file is only
+//truncated if every LogEntry was replayed and flushed without error, so none
+//are lost if replaying fails partway through, at the cost of those already
+//sent being sent again by the next replay.
+//
+//It should be called before the file is opened by NewWALBackend, as records
+//appended in the meantime would be truncated without being replayed.
+func ReplayAndTruncateWAL(path string, backends ...Backend) (int, error) {
+	replayed, err := ReplayWAL(path, backends...)
+	if err != nil {
+		return replayed, err
+	}
+
+	for _, backend := range backends {
+		if f, ok := backend.(Flusher); ok {
+			if err := f.Flush(); err != nil {
+				return replayed, fmt.Errorf("WAL Backend: unable to flush replayed LogEntry objects: %s", err)
+			}
+		}
+	}
+
+	if err := os.Truncate(path, 0); err != nil {
+		return replayed, fmt.Errorf("WAL Backend: unable to truncate log file: %s", err)
+	}
+	return replayed, nil
+}
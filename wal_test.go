@@ -0,0 +1,172 @@
+package lumberjack
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestWALBackendReplay(t *testing.T) {
+	path, cleanup := tempLogPath(t)
+	defer cleanup()
+
+	backend, err := NewWALBackend(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger := NewLogger()
+	logger.AddLevel(INFO)
+	logger.AddLevel(ERROR)
+	logger.AddBackend("wal", backend)
+
+	logger.Info("first")
+	logger.WithField("user", "bob").Error("second")
+	logger.Info("third")
+
+	// Simulate a crash: the backend is never closed, and the last record was
+	// cut short partway through being written.
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Write([]byte{0, 0, 0, 50, '{', '"'})
+	file.Close()
+
+	capture := &captureBackend{}
+	replayed, err := ReplayWAL(path, capture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, replayed, 3)
+
+	entries := capture.Entries()
+	expect(t, len(entries), 3)
+	expect(t, entries[0].Message, "first")
+	expect(t, entries[1].Level, ERROR)
+	expect(t, entries[1].Message, "second")
+	expect(t, entries[1].Fields["user"], "bob")
+	expect(t, entries[1].File, "wal_test.go")
+	expect(t, entries[2].Message, "third")
+}
+
+func TestWALBackendSyncInterval(t *testing.T) {
+	path, cleanup := tempLogPath(t)
+	defer cleanup()
+
+	backend, err := NewWALBackend(path, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry := testobj.Entries[0]
+	backend.Log(&entry)
+	expect(t, backend.dirty, true)
+
+	if err := backend.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, backend.dirty, false)
+
+	if err := backend.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Logging after Close is a no-op.
+	backend.Log(&entry)
+
+	capture := &captureBackend{}
+	replayed, err := ReplayWAL(path, capture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, replayed, 1)
+	expect(t, capture.Entries()[0].Message, "Test Error")
+}
+
+func TestReplayWALCorrupt(t *testing.T) {
+	path, cleanup := tempLogPath(t)
+	defer cleanup()
+
+	data := []byte{0, 0, 0, 3, 'b', 'a', 'd'}
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ReplayWAL(path); err == nil {
+		t.Error("Expected an error replaying a corrupt record")
+	}
+
+	if _, err := ReplayWAL(path + ".missing"); err == nil {
+		t.Error("Expected an error replaying a missing file")
+	}
+}
+
+func TestReplayAndTruncateWAL(t *testing.T) {
+	path, cleanup := tempLogPath(t)
+	defer cleanup()
+
+	backend, err := NewWALBackend(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := testobj.Entries[0]
+	backend.Log(&entry)
+	backend.Close()
+
+	capture := &captureBackend{}
+	replayed, err := ReplayAndTruncateWAL(path, capture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, replayed, 1)
+
+	// Replaying again after a restart doesn't send the entry twice.
+	replayed, err = ReplayAndTruncateWAL(path, capture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, replayed, 0)
+	expect(t, len(capture.Entries()), 1)
+
+	// New records are appended to the emptied file and replayed next time.
+	backend, err = NewWALBackend(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry = testobj.Entries[1]
+	backend.Log(&entry)
+	backend.Close()
+
+	replayed, err = ReplayAndTruncateWAL(path, capture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, replayed, 1)
+
+	entries := capture.Entries()
+	expect(t, len(entries), 2)
+	expect(t, entries[1].Message, testobj.Entries[1].Message)
+}
+
+func TestReplayAndTruncateWALCorrupt(t *testing.T) {
+	path, cleanup := tempLogPath(t)
+	defer cleanup()
+
+	data := []byte{0, 0, 0, 3, 'b', 'a', 'd'}
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ReplayAndTruncateWAL(path); err == nil {
+		t.Error("Expected an error replaying a corrupt record")
+	}
+
+	// The file is left for inspection rather than truncated.
+	kept, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, kept, data)
+}
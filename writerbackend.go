@@ -0,0 +1,38 @@
+package lumberjack
+
+import (
+	"io"
+	"sync"
+)
+
+//WriterBackend implements a Backend that renders each LogEntry with a
+//Formatter and writes it to an io.Writer followed by a newline, such as
+//RFC5424 syslog lines with the Syslog5424Formatter written to os.Stdout.
+type WriterBackend struct {
+	writer    io.Writer
+	formatter Formatter
+	sync.Mutex
+}
+
+//NewWriterBackend returns an instance of WriterBackend that writes each
+//LogEntry rendered by the specified Formatter to the specified io.Writer.
+func NewWriterBackend(w io.Writer, formatter Formatter) *WriterBackend {
+	return &WriterBackend{writer: w, formatter: formatter}
+}
+
+//Log satisfies the Backend interfaces requirements used for accepting
+//LogEntry objects to render and write out.
+func (b *WriterBackend) Log(entry *LogEntry) {
+	data, err := b.formatter.Format(entry)
+	if err != nil {
+		logInternalf(ERROR, "Writer Backend: unable to format LogEntry: %s", err)
+		return
+	}
+
+	b.Lock()
+	defer b.Unlock()
+
+	if _, err := b.writer.Write(append(data, '\n')); err != nil {
+		logInternalf(ERROR, "Writer Backend: unable to write LogEntry: %s", err)
+	}
+}